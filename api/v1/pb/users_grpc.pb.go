@@ -29,6 +29,28 @@ type UsersClient interface {
 	GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*GetTodoReply, error)
 	DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*DeleteTodoReply, error)
 	UpdateTodo(ctx context.Context, in *UpdateTodoRequest, opts ...grpc.CallOption) (*UpdateTodoReply, error)
+	BatchTodos(ctx context.Context, in *BatchTodosRequest, opts ...grpc.CallOption) (*BatchTodosReply, error)
+	SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchTodosReply, error)
+	GetTodayTodos(ctx context.Context, in *GetTodayTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error)
+	GetUpcomingTodos(ctx context.Context, in *GetUpcomingTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error)
+	GetOverdueTodos(ctx context.Context, in *GetOverdueTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error)
+	GetSomedayTodos(ctx context.Context, in *GetSomedayTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error)
+	GetActivity(ctx context.Context, in *GetActivityRequest, opts ...grpc.CallOption) (*GetActivityReply, error)
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsReply, error)
+	GetAgenda(ctx context.Context, in *GetAgendaRequest, opts ...grpc.CallOption) (*GetAgendaReply, error)
+	GetNotifications(ctx context.Context, in *GetNotificationsRequest, opts ...grpc.CallOption) (*GetNotificationsReply, error)
+	GetUnreadCount(ctx context.Context, in *GetUnreadCountRequest, opts ...grpc.CallOption) (*GetUnreadCountReply, error)
+	ReadNotification(ctx context.Context, in *ReadNotificationRequest, opts ...grpc.CallOption) (*ReadNotificationReply, error)
+	ReadAllNotifications(ctx context.Context, in *ReadAllNotificationsRequest, opts ...grpc.CallOption) (*ReadAllNotificationsReply, error)
+	GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*GetBoardReply, error)
+	UpdateBoard(ctx context.Context, in *UpdateBoardRequest, opts ...grpc.CallOption) (*UpdateBoardReply, error)
+	MoveCard(ctx context.Context, in *MoveCardRequest, opts ...grpc.CallOption) (*MoveCardReply, error)
+	AddView(ctx context.Context, in *AddViewRequest, opts ...grpc.CallOption) (*AddViewReply, error)
+	GetAllViews(ctx context.Context, in *GetAllViewsRequest, opts ...grpc.CallOption) (*GetAllViewsReply, error)
+	GetView(ctx context.Context, in *GetViewRequest, opts ...grpc.CallOption) (*GetViewReply, error)
+	UpdateView(ctx context.Context, in *UpdateViewRequest, opts ...grpc.CallOption) (*UpdateViewReply, error)
+	DeleteView(ctx context.Context, in *DeleteViewRequest, opts ...grpc.CallOption) (*DeleteViewReply, error)
+	GetViewTodos(ctx context.Context, in *GetViewTodosRequest, opts ...grpc.CallOption) (*GetViewTodosReply, error)
 }
 
 type usersClient struct {
@@ -138,6 +160,204 @@ func (c *usersClient) UpdateTodo(ctx context.Context, in *UpdateTodoRequest, opt
 	return out, nil
 }
 
+func (c *usersClient) BatchTodos(ctx context.Context, in *BatchTodosRequest, opts ...grpc.CallOption) (*BatchTodosReply, error) {
+	out := new(BatchTodosReply)
+	err := c.cc.Invoke(ctx, "/users.Users/BatchTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchTodosReply, error) {
+	out := new(SearchTodosReply)
+	err := c.cc.Invoke(ctx, "/users.Users/SearchTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetTodayTodos(ctx context.Context, in *GetTodayTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error) {
+	out := new(SmartListReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetTodayTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetUpcomingTodos(ctx context.Context, in *GetUpcomingTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error) {
+	out := new(SmartListReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetUpcomingTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetOverdueTodos(ctx context.Context, in *GetOverdueTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error) {
+	out := new(SmartListReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetOverdueTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetSomedayTodos(ctx context.Context, in *GetSomedayTodosRequest, opts ...grpc.CallOption) (*SmartListReply, error) {
+	out := new(SmartListReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetSomedayTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetActivity(ctx context.Context, in *GetActivityRequest, opts ...grpc.CallOption) (*GetActivityReply, error) {
+	out := new(GetActivityReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetActivity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsReply, error) {
+	out := new(GetStatsReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetAgenda(ctx context.Context, in *GetAgendaRequest, opts ...grpc.CallOption) (*GetAgendaReply, error) {
+	out := new(GetAgendaReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetAgenda", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetNotifications(ctx context.Context, in *GetNotificationsRequest, opts ...grpc.CallOption) (*GetNotificationsReply, error) {
+	out := new(GetNotificationsReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetUnreadCount(ctx context.Context, in *GetUnreadCountRequest, opts ...grpc.CallOption) (*GetUnreadCountReply, error) {
+	out := new(GetUnreadCountReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetUnreadCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) ReadNotification(ctx context.Context, in *ReadNotificationRequest, opts ...grpc.CallOption) (*ReadNotificationReply, error) {
+	out := new(ReadNotificationReply)
+	err := c.cc.Invoke(ctx, "/users.Users/ReadNotification", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) ReadAllNotifications(ctx context.Context, in *ReadAllNotificationsRequest, opts ...grpc.CallOption) (*ReadAllNotificationsReply, error) {
+	out := new(ReadAllNotificationsReply)
+	err := c.cc.Invoke(ctx, "/users.Users/ReadAllNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetBoard(ctx context.Context, in *GetBoardRequest, opts ...grpc.CallOption) (*GetBoardReply, error) {
+	out := new(GetBoardReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) UpdateBoard(ctx context.Context, in *UpdateBoardRequest, opts ...grpc.CallOption) (*UpdateBoardReply, error) {
+	out := new(UpdateBoardReply)
+	err := c.cc.Invoke(ctx, "/users.Users/UpdateBoard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) MoveCard(ctx context.Context, in *MoveCardRequest, opts ...grpc.CallOption) (*MoveCardReply, error) {
+	out := new(MoveCardReply)
+	err := c.cc.Invoke(ctx, "/users.Users/MoveCard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) AddView(ctx context.Context, in *AddViewRequest, opts ...grpc.CallOption) (*AddViewReply, error) {
+	out := new(AddViewReply)
+	err := c.cc.Invoke(ctx, "/users.Users/AddView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetAllViews(ctx context.Context, in *GetAllViewsRequest, opts ...grpc.CallOption) (*GetAllViewsReply, error) {
+	out := new(GetAllViewsReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetAllViews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetView(ctx context.Context, in *GetViewRequest, opts ...grpc.CallOption) (*GetViewReply, error) {
+	out := new(GetViewReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) UpdateView(ctx context.Context, in *UpdateViewRequest, opts ...grpc.CallOption) (*UpdateViewReply, error) {
+	out := new(UpdateViewReply)
+	err := c.cc.Invoke(ctx, "/users.Users/UpdateView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) DeleteView(ctx context.Context, in *DeleteViewRequest, opts ...grpc.CallOption) (*DeleteViewReply, error) {
+	out := new(DeleteViewReply)
+	err := c.cc.Invoke(ctx, "/users.Users/DeleteView", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) GetViewTodos(ctx context.Context, in *GetViewTodosRequest, opts ...grpc.CallOption) (*GetViewTodosReply, error) {
+	out := new(GetViewTodosReply)
+	err := c.cc.Invoke(ctx, "/users.Users/GetViewTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UsersServer is the server API for Users service.
 // All implementations must embed UnimplementedUsersServer
 // for forward compatibility
@@ -153,6 +373,28 @@ type UsersServer interface {
 	GetTodo(context.Context, *GetTodoRequest) (*GetTodoReply, error)
 	DeleteTodo(context.Context, *DeleteTodoRequest) (*DeleteTodoReply, error)
 	UpdateTodo(context.Context, *UpdateTodoRequest) (*UpdateTodoReply, error)
+	BatchTodos(context.Context, *BatchTodosRequest) (*BatchTodosReply, error)
+	SearchTodos(context.Context, *SearchTodosRequest) (*SearchTodosReply, error)
+	GetTodayTodos(context.Context, *GetTodayTodosRequest) (*SmartListReply, error)
+	GetUpcomingTodos(context.Context, *GetUpcomingTodosRequest) (*SmartListReply, error)
+	GetOverdueTodos(context.Context, *GetOverdueTodosRequest) (*SmartListReply, error)
+	GetSomedayTodos(context.Context, *GetSomedayTodosRequest) (*SmartListReply, error)
+	GetActivity(context.Context, *GetActivityRequest) (*GetActivityReply, error)
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsReply, error)
+	GetAgenda(context.Context, *GetAgendaRequest) (*GetAgendaReply, error)
+	GetNotifications(context.Context, *GetNotificationsRequest) (*GetNotificationsReply, error)
+	GetUnreadCount(context.Context, *GetUnreadCountRequest) (*GetUnreadCountReply, error)
+	ReadNotification(context.Context, *ReadNotificationRequest) (*ReadNotificationReply, error)
+	ReadAllNotifications(context.Context, *ReadAllNotificationsRequest) (*ReadAllNotificationsReply, error)
+	GetBoard(context.Context, *GetBoardRequest) (*GetBoardReply, error)
+	UpdateBoard(context.Context, *UpdateBoardRequest) (*UpdateBoardReply, error)
+	MoveCard(context.Context, *MoveCardRequest) (*MoveCardReply, error)
+	AddView(context.Context, *AddViewRequest) (*AddViewReply, error)
+	GetAllViews(context.Context, *GetAllViewsRequest) (*GetAllViewsReply, error)
+	GetView(context.Context, *GetViewRequest) (*GetViewReply, error)
+	UpdateView(context.Context, *UpdateViewRequest) (*UpdateViewReply, error)
+	DeleteView(context.Context, *DeleteViewRequest) (*DeleteViewReply, error)
+	GetViewTodos(context.Context, *GetViewTodosRequest) (*GetViewTodosReply, error)
 	mustEmbedUnimplementedUsersServer()
 }
 
@@ -193,6 +435,72 @@ func (UnimplementedUsersServer) DeleteTodo(context.Context, *DeleteTodoRequest)
 func (UnimplementedUsersServer) UpdateTodo(context.Context, *UpdateTodoRequest) (*UpdateTodoReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodo not implemented")
 }
+func (UnimplementedUsersServer) BatchTodos(context.Context, *BatchTodosRequest) (*BatchTodosReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchTodos not implemented")
+}
+func (UnimplementedUsersServer) SearchTodos(context.Context, *SearchTodosRequest) (*SearchTodosReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTodos not implemented")
+}
+func (UnimplementedUsersServer) GetTodayTodos(context.Context, *GetTodayTodosRequest) (*SmartListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodayTodos not implemented")
+}
+func (UnimplementedUsersServer) GetUpcomingTodos(context.Context, *GetUpcomingTodosRequest) (*SmartListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUpcomingTodos not implemented")
+}
+func (UnimplementedUsersServer) GetOverdueTodos(context.Context, *GetOverdueTodosRequest) (*SmartListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOverdueTodos not implemented")
+}
+func (UnimplementedUsersServer) GetSomedayTodos(context.Context, *GetSomedayTodosRequest) (*SmartListReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSomedayTodos not implemented")
+}
+func (UnimplementedUsersServer) GetActivity(context.Context, *GetActivityRequest) (*GetActivityReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActivity not implemented")
+}
+func (UnimplementedUsersServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedUsersServer) GetAgenda(context.Context, *GetAgendaRequest) (*GetAgendaReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgenda not implemented")
+}
+func (UnimplementedUsersServer) GetNotifications(context.Context, *GetNotificationsRequest) (*GetNotificationsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotifications not implemented")
+}
+func (UnimplementedUsersServer) GetUnreadCount(context.Context, *GetUnreadCountRequest) (*GetUnreadCountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUnreadCount not implemented")
+}
+func (UnimplementedUsersServer) ReadNotification(context.Context, *ReadNotificationRequest) (*ReadNotificationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadNotification not implemented")
+}
+func (UnimplementedUsersServer) ReadAllNotifications(context.Context, *ReadAllNotificationsRequest) (*ReadAllNotificationsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadAllNotifications not implemented")
+}
+func (UnimplementedUsersServer) GetBoard(context.Context, *GetBoardRequest) (*GetBoardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBoard not implemented")
+}
+func (UnimplementedUsersServer) UpdateBoard(context.Context, *UpdateBoardRequest) (*UpdateBoardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBoard not implemented")
+}
+func (UnimplementedUsersServer) MoveCard(context.Context, *MoveCardRequest) (*MoveCardReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCard not implemented")
+}
+func (UnimplementedUsersServer) AddView(context.Context, *AddViewRequest) (*AddViewReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddView not implemented")
+}
+func (UnimplementedUsersServer) GetAllViews(context.Context, *GetAllViewsRequest) (*GetAllViewsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllViews not implemented")
+}
+func (UnimplementedUsersServer) GetView(context.Context, *GetViewRequest) (*GetViewReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetView not implemented")
+}
+func (UnimplementedUsersServer) UpdateView(context.Context, *UpdateViewRequest) (*UpdateViewReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateView not implemented")
+}
+func (UnimplementedUsersServer) DeleteView(context.Context, *DeleteViewRequest) (*DeleteViewReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteView not implemented")
+}
+func (UnimplementedUsersServer) GetViewTodos(context.Context, *GetViewTodosRequest) (*GetViewTodosReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetViewTodos not implemented")
+}
 func (UnimplementedUsersServer) mustEmbedUnimplementedUsersServer() {}
 
 // UnsafeUsersServer may be embedded to opt out of forward compatibility for this service.
@@ -404,6 +712,402 @@ func _Users_UpdateTodo_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Users_BatchTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).BatchTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/BatchTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).BatchTodos(ctx, req.(*BatchTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_SearchTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).SearchTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/SearchTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).SearchTodos(ctx, req.(*SearchTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetTodayTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodayTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetTodayTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetTodayTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetTodayTodos(ctx, req.(*GetTodayTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetUpcomingTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUpcomingTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetUpcomingTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetUpcomingTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetUpcomingTodos(ctx, req.(*GetUpcomingTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetOverdueTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOverdueTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetOverdueTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetOverdueTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetOverdueTodos(ctx, req.(*GetOverdueTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetSomedayTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSomedayTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetSomedayTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetSomedayTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetSomedayTodos(ctx, req.(*GetSomedayTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetActivity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetActivity(ctx, req.(*GetActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetAgenda_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgendaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetAgenda(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetAgenda",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetAgenda(ctx, req.(*GetAgendaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetNotifications(ctx, req.(*GetNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetUnreadCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUnreadCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetUnreadCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetUnreadCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetUnreadCount(ctx, req.(*GetUnreadCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_ReadNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadNotificationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).ReadNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/ReadNotification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).ReadNotification(ctx, req.(*ReadNotificationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_ReadAllNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAllNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).ReadAllNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/ReadAllNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).ReadAllNotifications(ctx, req.(*ReadAllNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetBoard(ctx, req.(*GetBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_UpdateBoard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBoardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).UpdateBoard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/UpdateBoard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).UpdateBoard(ctx, req.(*UpdateBoardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_MoveCard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).MoveCard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/MoveCard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).MoveCard(ctx, req.(*MoveCardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_AddView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).AddView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/AddView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).AddView(ctx, req.(*AddViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetAllViews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllViewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetAllViews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetAllViews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetAllViews(ctx, req.(*GetAllViewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetView(ctx, req.(*GetViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_UpdateView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).UpdateView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/UpdateView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).UpdateView(ctx, req.(*UpdateViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_DeleteView_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteViewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).DeleteView(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/DeleteView",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).DeleteView(ctx, req.(*DeleteViewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_GetViewTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetViewTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetViewTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/users.Users/GetViewTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetViewTodos(ctx, req.(*GetViewTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Users_ServiceDesc is the grpc.ServiceDesc for Users service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -455,6 +1159,94 @@ var Users_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateTodo",
 			Handler:    _Users_UpdateTodo_Handler,
 		},
+		{
+			MethodName: "BatchTodos",
+			Handler:    _Users_BatchTodos_Handler,
+		},
+		{
+			MethodName: "SearchTodos",
+			Handler:    _Users_SearchTodos_Handler,
+		},
+		{
+			MethodName: "GetTodayTodos",
+			Handler:    _Users_GetTodayTodos_Handler,
+		},
+		{
+			MethodName: "GetUpcomingTodos",
+			Handler:    _Users_GetUpcomingTodos_Handler,
+		},
+		{
+			MethodName: "GetOverdueTodos",
+			Handler:    _Users_GetOverdueTodos_Handler,
+		},
+		{
+			MethodName: "GetSomedayTodos",
+			Handler:    _Users_GetSomedayTodos_Handler,
+		},
+		{
+			MethodName: "GetActivity",
+			Handler:    _Users_GetActivity_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _Users_GetStats_Handler,
+		},
+		{
+			MethodName: "GetAgenda",
+			Handler:    _Users_GetAgenda_Handler,
+		},
+		{
+			MethodName: "GetNotifications",
+			Handler:    _Users_GetNotifications_Handler,
+		},
+		{
+			MethodName: "GetUnreadCount",
+			Handler:    _Users_GetUnreadCount_Handler,
+		},
+		{
+			MethodName: "ReadNotification",
+			Handler:    _Users_ReadNotification_Handler,
+		},
+		{
+			MethodName: "ReadAllNotifications",
+			Handler:    _Users_ReadAllNotifications_Handler,
+		},
+		{
+			MethodName: "GetBoard",
+			Handler:    _Users_GetBoard_Handler,
+		},
+		{
+			MethodName: "UpdateBoard",
+			Handler:    _Users_UpdateBoard_Handler,
+		},
+		{
+			MethodName: "MoveCard",
+			Handler:    _Users_MoveCard_Handler,
+		},
+		{
+			MethodName: "AddView",
+			Handler:    _Users_AddView_Handler,
+		},
+		{
+			MethodName: "GetAllViews",
+			Handler:    _Users_GetAllViews_Handler,
+		},
+		{
+			MethodName: "GetView",
+			Handler:    _Users_GetView_Handler,
+		},
+		{
+			MethodName: "UpdateView",
+			Handler:    _Users_UpdateView_Handler,
+		},
+		{
+			MethodName: "DeleteView",
+			Handler:    _Users_DeleteView_Handler,
+		},
+		{
+			MethodName: "GetViewTodos",
+			Handler:    _Users_GetViewTodos_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/pb/users.proto",
@@ -1,22 +1,51 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Storage drivers.
+const (
+	DriverPostgres = "postgres"
+	DriverEmbedded = "embedded" // single bbolt file at StoragePath
+	DriverMemory   = "memory"   // optionally persisted to SnapshotFile
+)
 
 // Config represents a config info used in application.
 type Config struct {
-	SecretKey string
-	DBUrl     string
-	GrpcPort  string
-	HTTPPort  string
+	SecretKey             string
+	StorageDriver         string
+	DBUrl                 string
+	StoragePath           string
+	GrpcPort              string
+	HTTPPort              string
+	NotificationRetention time.Duration
+	SnapshotFile          string
+	SnapshotInterval      time.Duration
+	OutboxInterval        time.Duration
+	ReminderLead          time.Duration // todos due within lead are reminded, 0 disables reminders
+	CacheSize             int           // users and todos cached in front of postgres, 0 disables cache
+	CacheTTL              time.Duration
 }
 
 // New returns config object.
 func New() *Config {
 	return &Config{
-		SecretKey: getEnv("SECRETKEY", ""),
-		DBUrl:     getEnv("DATABASE_URL", ""),
-		GrpcPort:  getEnv("GRPCPORT", ":5000"),
-		HTTPPort:  getEnv("HTTPPORT", ":5001"),
+		SecretKey:             getEnv("SECRETKEY", ""),
+		StorageDriver:         getEnv("STORAGE_DRIVER", DriverPostgres),
+		DBUrl:                 getEnv("DATABASE_URL", ""),
+		StoragePath:           getEnv("STORAGE_PATH", "todo.db"),
+		GrpcPort:              getEnv("GRPCPORT", ":5000"),
+		HTTPPort:              getEnv("HTTPPORT", ":5001"),
+		NotificationRetention: getEnvDuration("NOTIFICATION_RETENTION", 30*24*time.Hour),
+		SnapshotFile:          getEnv("SNAPSHOT_FILE", ""),
+		SnapshotInterval:      getEnvDuration("SNAPSHOT_INTERVAL", time.Minute),
+		OutboxInterval:        getEnvDuration("OUTBOX_INTERVAL", time.Second),
+		ReminderLead:          getEnvDuration("REMINDER_LEAD", time.Hour),
+		CacheSize:             getEnvInt("CACHE_SIZE", 1000),
+		CacheTTL:              getEnvDuration("CACHE_TTL", time.Minute),
 	}
 }
 
@@ -27,3 +56,23 @@ func getEnv(key string, defaultVal string) string {
 
 	return defaultVal
 }
+
+func getEnvInt(key string, defaultVal int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+
+	return defaultVal
+}
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+
+	return defaultVal
+}
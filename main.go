@@ -11,11 +11,18 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 	"todo/logger"
 	"todo/metrics"
+	"todo/model"
+	"todo/outbox"
 	"todo/server/grpcsrv"
 	"todo/server/httpsrv"
 	"todo/service"
+	"todo/storage"
+	"todo/storage/cached"
+	"todo/storage/embedded"
+	"todo/storage/inmemory"
 	"todo/storage/postgres"
 
 	conf "todo/config"
@@ -23,6 +30,7 @@ import (
 
 func init() {
 	prometheus.Register(metrics.TotalRequests)
+	prometheus.Register(metrics.CacheRequests)
 	//prometheus.Register(metrics.ResponseStatus)
 	//prometheus.Register(metrics.HttpDuration)
 }
@@ -34,16 +42,47 @@ func main() {
 	}
 	config := conf.New()
 
-	dbpool, err := pgxpool.Connect(context.Background(), config.DBUrl)
-	if err != nil {
-		log.Errorf("Unable to connect to database: %v\n", err)
+	var store storage.Storage
+	var memory *inmemory.InMemory
+	switch config.StorageDriver {
+	case conf.DriverPostgres:
+		dbpool, err := pgxpool.Connect(context.Background(), config.DBUrl)
+		if err != nil {
+			log.Errorf("Unable to connect to database: %v\n", err)
+			os.Exit(1)
+		}
+		defer dbpool.Close()
+
+		migrateDatabase(context.Background(), dbpool, log)
+		store = postgres.NewPostgresStorage(dbpool)
+		if config.CacheSize > 0 {
+			store = cached.NewCachedStorage(store, config.CacheSize, config.CacheTTL)
+		}
+	case conf.DriverEmbedded:
+		db, err := embedded.Open(config.StoragePath)
+		if err != nil {
+			log.Errorf("Unable to open storage file: %v\n", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+
+		log.Infof("Storage file %v opened. Current schema version: %v", config.StoragePath, db.SchemaVersion())
+		store = db
+	case conf.DriverMemory:
+		memory = inmemory.NewInMemoryStorage()
+		if config.SnapshotFile != "" {
+			if err := memory.LoadSnapshot(config.SnapshotFile); err != nil {
+				log.Errorf("Unable to load snapshot: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		store = memory
+	default:
+		log.Errorf("Unknown storage driver %q\n", config.StorageDriver)
 		os.Exit(1)
 	}
-	defer dbpool.Close()
 
-	migrateDatabase(context.Background(), dbpool, log)
-
-	service := service.NewService(postgres.NewPostgresStorage(dbpool), config)
+	service := service.NewService(store, config)
 
 	go func() {
 		lis, err := net.Listen("tcp", config.GrpcPort)
@@ -69,6 +108,19 @@ func main() {
 		cancel()
 	}()
 
+	go purgeNotifications(ctx, service, log)
+	go remindDueTodos(ctx, service, log)
+
+	dispatcher := outbox.NewDispatcher(store, log)
+	dispatcher.Subscribe(func(ctx context.Context, e model.Event) error {
+		log.Debugf("event %s %s delivered", e.Type, e.ID)
+		return nil
+	})
+	go dispatcher.Run(ctx, config.OutboxInterval)
+	if memory != nil && config.SnapshotFile != "" {
+		go saveSnapshots(ctx, memory, config, log)
+	}
+
 	server := httpsrv.NewHTTPServer(service, config, log)
 	httpServer := &http.Server{
 		Addr:    config.HTTPPort,
@@ -76,7 +128,7 @@ func main() {
 	}
 
 	go func() {
-		err = httpServer.ListenAndServe()
+		err := httpServer.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
 			log.Errorf("http failed to serve: %v", err)
 		}
@@ -85,12 +137,70 @@ func main() {
 
 	<-ctx.Done()
 
-	if err = httpServer.Shutdown(context.Background()); err != nil {
+	if err := httpServer.Shutdown(context.Background()); err != nil {
 		log.Infof("http server Shutdown Failed:%+s", err)
 	}
 
 	log.Infof("http server exited properly")
 
+	if memory != nil && config.SnapshotFile != "" {
+		if err := memory.SaveSnapshot(config.SnapshotFile); err != nil {
+			log.Errorf("Unable to save snapshot: %v", err)
+		}
+	}
+
+}
+
+func purgeNotifications(ctx context.Context, s service.Handlers, log logger.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PurgeNotifications(ctx); err != nil {
+			log.Errorf("Unable to purge notifications: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func remindDueTodos(ctx context.Context, s service.Handlers, log logger.Logger) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RemindDueTodos(ctx); err != nil {
+			log.Errorf("Unable to remind due todos: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// saveSnapshots saves in memory storage periodically, final snapshot is saved on shutdown.
+func saveSnapshots(ctx context.Context, memory *inmemory.InMemory, config *conf.Config, log logger.Logger) {
+	if config.SnapshotInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(config.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := memory.SaveSnapshot(config.SnapshotFile); err != nil {
+			log.Errorf("Unable to save snapshot: %v", err)
+		}
+	}
 }
 
 func migrateDatabase(ctx context.Context, dbpool *pgxpool.Pool, log logger.Logger) {
@@ -21,6 +21,14 @@ var ResponseStatus = prometheus.NewCounterVec(
 	[]string{"status"},
 )
 
+var CacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "storage_cache_requests_total",
+		Help: "Number of storage cache lookups by kind and result (hit or miss).",
+	},
+	[]string{"kind", "result"},
+)
+
 var HttpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 	Name: "http_response_time_seconds",
 	Help: "Duration of HTTP requests.",
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Activity event types.
+const (
+	ActivityTodoCreated   = "todo.created"
+	ActivityTodoUpdated   = "todo.updated"
+	ActivityTodoCompleted = "todo.completed"
+	ActivityTodoDeleted   = "todo.deleted"
+)
+
+// Activity represents single event in activity feed.
+type Activity struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	UserID   string    `json:"userid"`
+	TodoID   string    `json:"todoid,omitempty"`
+	TodoName string    `json:"todoname,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// ActivityPage represents one page of activity feed.
+type ActivityPage struct {
+	Activities []Activity `json:"activities"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
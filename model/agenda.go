@@ -0,0 +1,16 @@
+package model
+
+// Agenda represents users todos grouped by local days.
+// Days are listed from From to To inclusive, days without todos included.
+type Agenda struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Location string      `json:"location"`
+	Days     []AgendaDay `json:"days"`
+}
+
+// AgendaDay represents todos of one local day, all-day todos first.
+type AgendaDay struct {
+	Date  string     `json:"date"` // 2006-01-02
+	Todos []TodoItem `json:"todos"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// Board represents kanban board of users todos.
+type Board struct {
+	UserID  string        `json:"-"`
+	Columns []BoardColumn `json:"columns"`
+}
+
+// BoardColumn represents board column holding todos of one status.
+// WIPLimit equal to 0 means the column is not limited.
+type BoardColumn struct {
+	Status   string     `json:"status"`
+	WIPLimit int        `json:"wiplimit"`
+	Cards    []TodoItem `json:"cards,omitempty"`
+}
+
+// CardMove represents target column and position of moved card.
+type CardMove struct {
+	Status   string `json:"status"`
+	Position int    `json:"position"`
+}
@@ -6,8 +6,11 @@ import (
 
 // ErrOperational errors model defined for application.
 var (
-	ErrOperational  = errors.New("operational")
-	ErrBadRequest   = errors.New("bad request")
-	ErrUnauthorized = errors.New("unauthorized")
-	ErrNotFound     = errors.New("forbidden")
+	ErrOperational     = errors.New("operational")
+	ErrBadRequest      = errors.New("bad request")
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrNotFound        = errors.New("forbidden")
+	ErrVersionConflict = errors.New("version conflict")
+	ErrUserHasTodos    = errors.New("user has todos")
+	ErrConflict        = errors.New("conflict")
 )
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types.
+const (
+	EventTodoCreated = "todo.created"
+	EventTodoUpdated = "todo.updated"
+	EventTodoDeleted = "todo.deleted"
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted" // todos deleted or reassigned with user are not reported separately
+)
+
+// Event represents domain event kept in outbox until it is delivered.
+// Payload is todo or user as of the change, deleted ones as they were before deletion.
+// Seq is assigned by storage in order events are added, events are delivered in Seq order.
+type Event struct {
+	ID      string          `json:"id"`
+	Seq     int64           `json:"seq"`
+	Type    string          `json:"type"`
+	UserID  string          `json:"userid"`
+	Payload json.RawMessage `json:"payload"`
+	Date    time.Time       `json:"date"`
+}
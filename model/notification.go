@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Notification types.
+const (
+	NotificationMention    = "mention"
+	NotificationAssignment = "assignment" // todos of deleted user were reassigned
+	NotificationReminder   = "reminder"   // todo is due soon
+)
+
+// Notification represents notification in users inbox.
+type Notification struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	UserID  string    `json:"-"`
+	ActorID string    `json:"actorid"`
+	TodoID  string    `json:"todoid,omitempty"`
+	Message string    `json:"message"`
+	Read    bool      `json:"read"`
+	Date    time.Time `json:"date"`
+}
+
+// NotificationPage represents one page of notifications.
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+}
+
+// UnreadCount represents number of unread notifications.
+type UnreadCount struct {
+	Unread int `json:"unread"`
+}
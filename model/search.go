@@ -0,0 +1,9 @@
+package model
+
+// TodoSearchResult represents todo matching search query.
+// Snippet contains HTML-escaped todo name with matched words wrapped in <b></b>.
+type TodoSearchResult struct {
+	Todo    TodoItem `json:"todo"`
+	Rank    float64  `json:"rank"`
+	Snippet string   `json:"snippet"`
+}
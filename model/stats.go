@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Stats bucket sizes.
+const (
+	BucketDay  = "day"
+	BucketWeek = "week"
+)
+
+// Stats represents todo throughput of user over date range.
+// Range is [From, To), bucket starts and range bounds are in users location.
+// AvgTimeToComplete is in seconds, 0 when nothing was completed in range.
+// Buckets count existing todos by their creation and completion times, so deleted todos
+// are left out. Todos saved before creation times were recorded are left out of buckets
+// and AvgTimeToComplete, Untracked counts them.
+type Stats struct {
+	From              time.Time      `json:"from"`
+	To                time.Time      `json:"to"`
+	Bucket            string         `json:"bucket"`
+	Buckets           []StatsBucket  `json:"buckets"`
+	ByStatus          map[string]int `json:"bystatus"`
+	AvgTimeToComplete float64        `json:"avgtimetocomplete"`
+	Overdue           int            `json:"overdue"`
+	Untracked         int            `json:"untracked"`
+}
+
+// StatsBucket represents number of todos created and completed in one bucket.
+type StatsBucket struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
@@ -2,16 +2,79 @@ package model
 
 import "time"
 
+// Todo statuses.
+const (
+	StatusNew  = "new"
+	StatusDone = "done"
+)
+
+// Smart lists of open todos relative to today in users location.
+const (
+	ListToday    = "today"    // due today
+	ListUpcoming = "upcoming" // due in the next days after today
+	ListOverdue  = "overdue"  // due before today
+	ListSomeday  = "someday"  // due after the upcoming days
+)
+
 // TodoItem represents todo.
+// Date of all-day todo is its calendar date at midnight UTC.
 type TodoItem struct {
-	ID     string    `json:"id"`
-	Name   string    `json:"name"`
-	Date   time.Time `json:"date"`
-	Status string    `json:"status"`
-	UserID string    `json:"-"`
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Date     time.Time `json:"date"`
+	AllDay   bool      `json:"allday"`
+	Status   string    `json:"status"`
+	Position int       `json:"position"`
+	Version  int       `json:"version"` // incremented on each update, starts at 1
+	UserID   string    `json:"-"`
+	// Created and Completed are kept by storage for stats, Completed is zero unless todo is done.
+	Created   time.Time `json:"-"`
+	Completed time.Time `json:"-"`
+}
+
+// DateIn returns todo date in location.
+// All-day todos keep their calendar date and stay in UTC.
+func (t TodoItem) DateIn(location *time.Location) time.Time {
+	if t.AllDay {
+		return t.Date.UTC()
+	}
+	return t.Date.In(location)
+}
+
+// AllDayDate returns midnight UTC of calendar date of t as seen in its own location.
+func AllDayDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
 
 // TodoID represents todos id.
 type TodoID struct {
 	ID string `json:"id"`
 }
+
+// TodoPage represents one page of todos.
+type TodoPage struct {
+	Todos      []TodoItem `json:"todos"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// TodoBatch represents todo changes applied together.
+// Version of updated or deleted todo is its expected version, 0 matches any version.
+type TodoBatch struct {
+	Add    []TodoItem `json:"add"`
+	Update []TodoItem `json:"update"`
+	Delete []TodoItem `json:"delete"` // only id and version are used
+}
+
+// TodoResult represents outcome of single todo of batch.
+type TodoResult struct {
+	ID      string `json:"id"`
+	Version int    `json:"version,omitempty"` // version after add or update
+	Error   string `json:"error,omitempty"`   // "not found", "duplicate id" or "version conflict", empty if todo was changed
+}
+
+// TodoBatchResult represents outcome of batch, results are in order of batch todos.
+type TodoBatchResult struct {
+	Add    []TodoResult `json:"add"`
+	Update []TodoResult `json:"update"`
+	Delete []TodoResult `json:"delete"`
+}
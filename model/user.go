@@ -18,6 +18,25 @@ type User struct {
 	Location  CustomLocation `json:"location" db:"location"` // time.Location
 }
 
+// Policies for todos of deleted user.
+const (
+	DeleteTodosRefuse   = "refuse"   // user with todos is not deleted
+	DeleteTodosCascade  = "cascade"  // todos are deleted with user
+	DeleteTodosReassign = "reassign" // todos are moved to another user
+)
+
+// UserDeletion tells what happens to todos of deleted user.
+type UserDeletion struct {
+	Todos      string `json:"todos"`      // policy, refuse if empty
+	ReassignTo string `json:"reassignto"` // user receiving todos for reassign policy
+}
+
+// UserPage represents one page of users.
+type UserPage struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 // NewUser represents users structure with password.
 type NewUser struct {
 	UserName  string         `json:"username" db:"username"`
@@ -71,3 +90,20 @@ func (c CustomLocation) MarshalJSON() ([]byte, error) {
 	// return []byte(fmt.Sprintf(`"%s"`, c.Location.String())), nil
 	return json.Marshal(c.Location.String())
 }
+
+// GobEncode used to encode CustomLocation in storage snapshots.
+func (c CustomLocation) GobEncode() ([]byte, error) {
+	if c.Location == nil {
+		return nil, nil
+	}
+	return []byte(c.Location.String()), nil
+}
+
+// GobDecode used to decode CustomLocation from storage snapshots.
+func (c *CustomLocation) GobDecode(b []byte) (err error) {
+	if len(b) == 0 {
+		return nil
+	}
+	c.Location, err = time.LoadLocation(string(b))
+	return
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// View represents saved todos filter with sort order.
+type View struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Status   string     `json:"status,omitempty"`
+	FromDate *time.Time `json:"fromdate,omitempty"`
+	ToDate   *time.Time `json:"todate,omitempty"`
+	Query    string     `json:"query,omitempty"`
+	Sort     string     `json:"sort,omitempty"`
+	UserID   string     `json:"-"`
+}
@@ -0,0 +1,96 @@
+// Package outbox delivers domain events from storage outbox to in-process handlers.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/logger"
+	"todo/model"
+	"todo/storage"
+)
+
+// batchSize is number of events read from outbox at once.
+const batchSize = 100
+
+// Handler handles delivered event.
+// Events are delivered at least once, so handler must tolerate duplicates.
+type Handler func(ctx context.Context, e model.Event) error
+
+// Dispatcher delivers events written to outbox to subscribed handlers.
+// Event is removed from outbox only after all its handlers succeed,
+// failed event is retried before any later one is delivered.
+type Dispatcher struct {
+	storage  storage.Storage
+	log      logger.Logger
+	handlers map[string][]Handler // event type -> handlers, "" for handlers of all events
+}
+
+// NewDispatcher returns dispatcher reading events from storage.
+func NewDispatcher(s storage.Storage, log logger.Logger) *Dispatcher {
+	return &Dispatcher{storage: s, log: log, handlers: map[string][]Handler{}}
+}
+
+// Subscribe registers handler for events of given types, for all events if no type is given.
+// Handlers must be subscribed before dispatcher runs.
+func (d *Dispatcher) Subscribe(h Handler, types ...string) {
+	if len(types) == 0 {
+		types = []string{""}
+	}
+	for _, t := range types {
+		d.handlers[t] = append(d.handlers[t], h)
+	}
+}
+
+// Run dispatches events every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Dispatch(ctx); err != nil {
+			d.log.Errorf("Unable to dispatch events: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch delivers pending events oldest first until outbox is empty or delivery fails.
+// It returns number of delivered events.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		events, err := d.storage.GetEvents(ctx, batchSize)
+		if err != nil {
+			return delivered, fmt.Errorf("cant get events: %v", err)
+		}
+		for _, e := range events {
+			if err := d.deliver(ctx, e); err != nil {
+				return delivered, err
+			}
+			if err := d.storage.DeleteEvent(ctx, e.ID); err != nil {
+				return delivered, fmt.Errorf("cant delete event %s: %v", e.ID, err)
+			}
+			delivered++
+		}
+		if len(events) < batchSize || ctx.Err() != nil {
+			return delivered, nil
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, e model.Event) error {
+	for _, handlers := range [][]Handler{d.handlers[e.Type], d.handlers[""]} {
+		for _, h := range handlers {
+			if err := h(ctx, e); err != nil {
+				return fmt.Errorf("cant deliver %s event %s: %v", e.Type, e.ID, err)
+			}
+		}
+	}
+	return nil
+}
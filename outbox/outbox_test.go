@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"todo/logger"
+	"todo/model"
+	"todo/storage/inmemory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	s := inmemory.NewInMemoryStorage()
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	add := func(eventType string, date time.Time) string {
+		id, err := s.AddEvent(ctx, model.Event{Type: eventType, Payload: []byte(`{}`), Date: date})
+		assert.NoError(t, err)
+		return id
+	}
+
+	d := NewDispatcher(s, logger.New(ioutil.Discard))
+	var all, created []string
+	fail := true
+	d.Subscribe(func(ctx context.Context, e model.Event) error {
+		all = append(all, e.ID)
+		return nil
+	})
+	d.Subscribe(func(ctx context.Context, e model.Event) error {
+		created = append(created, e.ID)
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	}, model.EventTodoCreated, model.EventUserCreated)
+
+	t.Run("delivers events in order", func(t *testing.T) {
+		fail = false
+		first := add(model.EventTodoUpdated, date)
+		second := add(model.EventTodoCreated, date.Add(time.Minute))
+
+		n, err := d.Dispatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{first, second}, all)
+		assert.Equal(t, []string{second}, created)
+
+		events, err := s.GetEvents(ctx, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("retries failed event", func(t *testing.T) {
+		fail = true
+		all, created = nil, nil
+		failing := add(model.EventUserCreated, date.Add(2*time.Minute))
+		later := add(model.EventTodoDeleted, date.Add(3*time.Minute))
+
+		n, err := d.Dispatch(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, []string{failing}, created)
+		assert.Empty(t, all, "later events wait for failed one")
+
+		fail = false
+		n, err = d.Dispatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{failing, failing}, created, "event is delivered again")
+		assert.Equal(t, []string{failing, later}, all)
+	})
+}
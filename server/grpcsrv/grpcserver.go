@@ -6,7 +6,9 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
 	"time"
 	"todo/api/v1/pb"
 	"todo/config"
@@ -14,6 +16,7 @@ import (
 	"todo/model"
 	"todo/service"
 	"todo/storage"
+	"todo/storage/query"
 
 	"google.golang.org/grpc"
 )
@@ -30,7 +33,7 @@ type Server struct {
 func NewGrpcServer(s service.Handlers, c *config.Config, l logger.Logger) *grpc.Server {
 	authMD := AuthMD{service: s, config: c}
 	opts := make([]grpc.ServerOption, 0)
-	opts = append(opts, grpc.ChainUnaryInterceptor(authMD.UnaryInterceptor()))
+	opts = append(opts, grpc.ChainUnaryInterceptor(ErrorInterceptor(), authMD.UnaryInterceptor()))
 
 	server := grpc.NewServer(opts...)
 	pb.RegisterUsersServer(server, &Server{
@@ -103,7 +106,8 @@ func (s *Server) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.
 		return nil, fmt.Errorf("%q: %w", "userid is not provided.", model.ErrBadRequest)
 	}
 
-	err := s.service.DeleteUser(ctx, userid[0])
+	deletion := model.UserDeletion{Todos: in.GetTodos(), ReassignTo: in.GetReassignTo()}
+	err := s.service.DeleteUser(ctx, userid[0], deletion)
 	if err != nil {
 		s.log.Errorf("%q: %w", "Could not delete user.", err)
 		return nil, err
@@ -113,7 +117,17 @@ func (s *Server) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.
 
 // GetAllUsers get all users handler.
 func (s *Server) GetAllUsers(ctx context.Context, in *pb.GetAllUsersRequest) (*pb.GetAllUsersReply, error) {
-	filter := storage.UserFilter{}
+	filter := storage.UserFilter{Limit: int(in.GetLimit()), Query: in.GetQuery()}
+	fields, err := maskFields(in.GetFields(), storage.UserFields)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get all users.", err)
+		return nil, err
+	}
+	if filter.Query != "" && len(fields) == 0 {
+		fields = storage.PublicUserFields
+	}
+	filter.Fields = fields
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Errorf(codes.InvalidArgument, "Retrieving metadata is failed")
@@ -124,21 +138,15 @@ func (s *Server) GetAllUsers(ctx context.Context, in *pb.GetAllUsersRequest) (*p
 		filter.UserName = username[0]
 	}
 
-	users, err := s.service.GetUsers(ctx, filter)
+	page, err := s.service.GetUsers(ctx, filter, in.GetCursor())
 	if err != nil {
 		s.log.Errorf("%q: 	", "Could not get all users.")
 		return nil, err
 	}
 
-	usersReply := &pb.GetAllUsersReply{}
-	for _, u := range users {
-		usersReply.Users = append(usersReply.Users, &pb.User{
-			Id:        u.ID,
-			UserName:  u.UserName,
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Location:  u.Location.String(),
-		})
+	usersReply := &pb.GetAllUsersReply{NextCursor: page.NextCursor}
+	for _, u := range page.Users {
+		usersReply.Users = append(usersReply.Users, userToPb(u, fields))
 	}
 	return usersReply, nil
 }
@@ -156,20 +164,20 @@ func (s *Server) GetUser(ctx context.Context, in *pb.GetUserRequest) (*pb.GetUse
 		return nil, fmt.Errorf("%q: %w", "userid is not provided.", model.ErrBadRequest)
 	}
 
-	user, err := s.service.GetUser(ctx, userid[0])
+	fields, err := maskFields(in.GetFields(), storage.UserFields)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get user.", err)
+		return nil, err
+	}
+
+	user, err := s.service.GetUser(ctx, userid[0], fields)
 	if err != nil {
 		s.log.Errorf("%q: %w", "Could not get user.", err)
 		return nil, err
 	}
 
 	userReply := &pb.GetUserReply{}
-	userReply.User = &pb.User{
-		Id:        user.ID,
-		UserName:  user.UserName,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Location:  user.Location.String(),
-	}
+	userReply.User = userToPb(user, fields)
 
 	return userReply, nil
 }
@@ -201,6 +209,7 @@ func (s *Server) AddTodo(ctx context.Context, in *pb.AddTodoRequest) (*pb.AddTod
 	todo := model.TodoItem{
 		Name:   in.Name,
 		Date:   in.Date.AsTime(),
+		AllDay: in.AllDay,
 		Status: in.Status,
 	}
 
@@ -246,20 +255,41 @@ func (s *Server) GetAllTodos(ctx context.Context, in *pb.GetAllTodosRequest) (*p
 		filter.ToDate = &toDate
 	}
 
-	todos, err := s.service.GetTodos(ctx, filter)
+	if in.GetQuery() != "" {
+		expr, err := query.Parse(in.GetQuery())
+		if err != nil {
+			s.log.Errorf("Could not parse query in GetAllTodos %v", err)
+			return nil, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrBadRequest)
+		}
+		filter.Query = expr
+	}
+
+	if in.GetSort() != "" {
+		sort, err := storage.ParseSort(in.GetSort())
+		if err != nil {
+			s.log.Errorf("Could not parse sort in GetAllTodos %v", err)
+			return nil, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrBadRequest)
+		}
+		filter.Sort = sort
+	}
+	filter.Limit = int(in.GetLimit())
+
+	fields, err := maskFields(in.GetFields(), storage.TodoFields)
+	if err != nil {
+		s.log.Errorf("Could not parse fields in GetAllTodos %v", err)
+		return nil, err
+	}
+	filter.Fields = fields
+
+	page, err := s.service.GetTodos(ctx, filter, in.GetCursor())
 	if err != nil {
 		s.log.Errorf("%q: 	", "Could not get all todos.")
 		return nil, err
 	}
 
-	todosReply := &pb.GetAllTodosReply{}
-	for _, u := range todos {
-		todosReply.Todos = append(todosReply.Todos, &pb.Todo{
-			Id:     u.ID,
-			Status: u.Status,
-			Name:   u.Name,
-			Date:   timestamppb.New(u.Date),
-		})
+	todosReply := &pb.GetAllTodosReply{NextCursor: page.NextCursor}
+	for _, u := range page.Todos {
+		todosReply.Todos = append(todosReply.Todos, todoFieldsToPb(u, fields))
 	}
 	return todosReply, nil
 }
@@ -277,19 +307,20 @@ func (s *Server) GetTodo(ctx context.Context, in *pb.GetTodoRequest) (*pb.GetTod
 		return nil, fmt.Errorf("%q: %w", "todoid is not provided.", model.ErrBadRequest)
 	}
 
-	todo, err := s.service.GetTodo(ctx, todoid[0])
+	fields, err := maskFields(in.GetFields(), storage.TodoFields)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get todo.", err)
+		return nil, err
+	}
+
+	todo, err := s.service.GetTodo(ctx, todoid[0], fields)
 	if err != nil {
 		s.log.Errorf("%q: %w", "Could not get todo.", err)
 		return nil, err
 	}
 
 	todoReply := &pb.GetTodoReply{}
-	todoReply.Todo = &pb.Todo{
-		Id:     todo.ID,
-		Name:   todo.Name,
-		Status: todo.Status,
-		Date:   timestamppb.New(todo.Date),
-	}
+	todoReply.Todo = todoFieldsToPb(todo, fields)
 
 	return todoReply, nil
 }
@@ -308,9 +339,11 @@ func (s *Server) UpdateTodo(ctx context.Context, in *pb.UpdateTodoRequest) (*pb.
 	}
 
 	todo := model.TodoItem{
-		Name:   in.Name,
-		Status: in.Status,
-		Date:   in.Date.AsTime(),
+		Name:    in.Name,
+		Status:  in.Status,
+		Date:    in.Date.AsTime(),
+		AllDay:  in.AllDay,
+		Version: int(in.Version),
 	}
 
 	err := s.service.UpdateTodo(ctx, todoid[0], todo)
@@ -322,6 +355,32 @@ func (s *Server) UpdateTodo(ctx context.Context, in *pb.UpdateTodoRequest) (*pb.
 	return &pb.UpdateTodoReply{}, nil
 }
 
+// BatchTodos batch todos handler.
+func (s *Server) BatchTodos(ctx context.Context, in *pb.BatchTodosRequest) (*pb.BatchTodosReply, error) {
+	batch := model.TodoBatch{}
+	for _, t := range in.GetAdd() {
+		batch.Add = append(batch.Add, todoFromPb(t))
+	}
+	for _, t := range in.GetUpdate() {
+		batch.Update = append(batch.Update, todoFromPb(t))
+	}
+	for _, t := range in.GetDelete() {
+		batch.Delete = append(batch.Delete, todoFromPb(t))
+	}
+
+	result, err := s.service.BatchTodos(ctx, batch)
+	if err != nil {
+		s.log.Errorf("Could not apply batch %v", err)
+		return nil, err
+	}
+
+	return &pb.BatchTodosReply{
+		Add:    todoResultsToPb(result.Add),
+		Update: todoResultsToPb(result.Update),
+		Delete: todoResultsToPb(result.Delete),
+	}, nil
+}
+
 // DeleteTodo delete todo handler.
 func (s *Server) DeleteTodo(ctx context.Context, in *pb.DeleteTodoRequest) (*pb.DeleteTodoReply, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -335,10 +394,446 @@ func (s *Server) DeleteTodo(ctx context.Context, in *pb.DeleteTodoRequest) (*pb.
 		return nil, fmt.Errorf("%q: %w", "todoid is not provided.", model.ErrBadRequest)
 	}
 
-	err := s.service.DeleteTodo(ctx, todoid[0])
+	err := s.service.DeleteTodo(ctx, todoid[0], int(in.GetVersion()))
 	if err != nil {
 		s.log.Errorf("%q: %w", "Could not delete todo.", err)
 		return nil, err
 	}
 	return &pb.DeleteTodoReply{}, nil
 }
+
+// SearchTodos search todos handler.
+func (s *Server) SearchTodos(ctx context.Context, in *pb.SearchTodosRequest) (*pb.SearchTodosReply, error) {
+	search := storage.TodoSearch{Query: in.GetQuery(), Limit: int(in.GetLimit())}
+
+	results, err := s.service.SearchTodos(ctx, search)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not search todos.", err)
+		return nil, err
+	}
+
+	searchReply := &pb.SearchTodosReply{}
+	for _, r := range results {
+		searchReply.Results = append(searchReply.Results, &pb.TodoSearchResult{
+			Todo:    todoToPb(r.Todo),
+			Rank:    r.Rank,
+			Snippet: r.Snippet,
+		})
+	}
+	return searchReply, nil
+}
+
+// GetTodayTodos get todos due today handler.
+func (s *Server) GetTodayTodos(ctx context.Context, in *pb.GetTodayTodosRequest) (*pb.SmartListReply, error) {
+	return s.smartList(ctx, model.ListToday, 0)
+}
+
+// GetUpcomingTodos get upcoming todos handler.
+func (s *Server) GetUpcomingTodos(ctx context.Context, in *pb.GetUpcomingTodosRequest) (*pb.SmartListReply, error) {
+	return s.smartList(ctx, model.ListUpcoming, int(in.GetDays()))
+}
+
+// GetOverdueTodos get overdue todos handler.
+func (s *Server) GetOverdueTodos(ctx context.Context, in *pb.GetOverdueTodosRequest) (*pb.SmartListReply, error) {
+	return s.smartList(ctx, model.ListOverdue, 0)
+}
+
+// GetSomedayTodos get someday todos handler.
+func (s *Server) GetSomedayTodos(ctx context.Context, in *pb.GetSomedayTodosRequest) (*pb.SmartListReply, error) {
+	return s.smartList(ctx, model.ListSomeday, int(in.GetDays()))
+}
+
+func (s *Server) smartList(ctx context.Context, list string, days int) (*pb.SmartListReply, error) {
+	todos, err := s.service.GetSmartList(ctx, list, days)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get todo list.", err)
+		return nil, err
+	}
+
+	listReply := &pb.SmartListReply{}
+	for _, todo := range todos {
+		listReply.Todos = append(listReply.Todos, todoToPb(todo))
+	}
+	return listReply, nil
+}
+
+// GetActivity get activity handler.
+func (s *Server) GetActivity(ctx context.Context, in *pb.GetActivityRequest) (*pb.GetActivityReply, error) {
+	filter := storage.ActivityFilter{
+		Types: in.GetTypes(),
+		Limit: int(in.GetLimit()),
+	}
+
+	page, err := s.service.GetActivity(ctx, filter, in.GetCursor())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get activity.", err)
+		return nil, err
+	}
+
+	activityReply := &pb.GetActivityReply{NextCursor: page.NextCursor}
+	for _, a := range page.Activities {
+		activityReply.Activities = append(activityReply.Activities, &pb.Activity{
+			Id:       a.ID,
+			Type:     a.Type,
+			UserId:   a.UserID,
+			TodoId:   a.TodoID,
+			TodoName: a.TodoName,
+			Date:     timestamppb.New(a.Date),
+		})
+	}
+	return activityReply, nil
+}
+
+// GetStats get stats handler.
+func (s *Server) GetStats(ctx context.Context, in *pb.GetStatsRequest) (*pb.GetStatsReply, error) {
+	stats, err := s.service.GetStats(ctx, in.GetFrom(), in.GetTo(), in.GetBucket())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get stats.", err)
+		return nil, err
+	}
+
+	statsReply := &pb.GetStatsReply{
+		From:              timestamppb.New(stats.From),
+		To:                timestamppb.New(stats.To),
+		Bucket:            stats.Bucket,
+		ByStatus:          map[string]int32{},
+		AvgTimeToComplete: stats.AvgTimeToComplete,
+		Overdue:           int32(stats.Overdue),
+		Untracked:         int32(stats.Untracked),
+	}
+	for status, count := range stats.ByStatus {
+		statsReply.ByStatus[status] = int32(count)
+	}
+	for _, b := range stats.Buckets {
+		statsReply.Buckets = append(statsReply.Buckets, &pb.StatsBucket{
+			Start:     timestamppb.New(b.Start),
+			Created:   int32(b.Created),
+			Completed: int32(b.Completed),
+		})
+	}
+	return statsReply, nil
+}
+
+// GetAgenda get agenda handler.
+func (s *Server) GetAgenda(ctx context.Context, in *pb.GetAgendaRequest) (*pb.GetAgendaReply, error) {
+	agenda, err := s.service.GetAgenda(ctx, in.GetFrom(), in.GetTo(), in.GetTz())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get agenda.", err)
+		return nil, err
+	}
+
+	agendaReply := &pb.GetAgendaReply{From: agenda.From, To: agenda.To, Location: agenda.Location}
+	for _, day := range agenda.Days {
+		d := &pb.AgendaDay{Date: day.Date}
+		for _, todo := range day.Todos {
+			d.Todos = append(d.Todos, todoToPb(todo))
+		}
+		agendaReply.Days = append(agendaReply.Days, d)
+	}
+	return agendaReply, nil
+}
+
+// GetNotifications get notifications handler.
+func (s *Server) GetNotifications(ctx context.Context, in *pb.GetNotificationsRequest) (*pb.GetNotificationsReply, error) {
+	filter := storage.NotificationFilter{
+		UnreadOnly: in.GetUnreadOnly(),
+		Limit:      int(in.GetLimit()),
+	}
+
+	page, err := s.service.GetNotifications(ctx, filter, in.GetCursor())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get notifications.", err)
+		return nil, err
+	}
+
+	notificationsReply := &pb.GetNotificationsReply{Unread: int32(page.Unread), NextCursor: page.NextCursor}
+	for _, n := range page.Notifications {
+		notificationsReply.Notifications = append(notificationsReply.Notifications, &pb.Notification{
+			Id:      n.ID,
+			Type:    n.Type,
+			ActorId: n.ActorID,
+			TodoId:  n.TodoID,
+			Message: n.Message,
+			Read:    n.Read,
+			Date:    timestamppb.New(n.Date),
+		})
+	}
+	return notificationsReply, nil
+}
+
+// GetUnreadCount get unread notifications count handler.
+func (s *Server) GetUnreadCount(ctx context.Context, in *pb.GetUnreadCountRequest) (*pb.GetUnreadCountReply, error) {
+	count, err := s.service.CountUnreadNotifications(ctx)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not count notifications.", err)
+		return nil, err
+	}
+	return &pb.GetUnreadCountReply{Unread: int32(count.Unread)}, nil
+}
+
+// ReadNotification read notification handler.
+func (s *Server) ReadNotification(ctx context.Context, in *pb.ReadNotificationRequest) (*pb.ReadNotificationReply, error) {
+	err := s.service.ReadNotification(ctx, in.GetId())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not read notification.", err)
+		return nil, err
+	}
+	return &pb.ReadNotificationReply{}, nil
+}
+
+// ReadAllNotifications read all notifications handler.
+func (s *Server) ReadAllNotifications(ctx context.Context, in *pb.ReadAllNotificationsRequest) (*pb.ReadAllNotificationsReply, error) {
+	err := s.service.ReadAllNotifications(ctx)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not read notifications.", err)
+		return nil, err
+	}
+	return &pb.ReadAllNotificationsReply{}, nil
+}
+
+// GetBoard get board handler.
+func (s *Server) GetBoard(ctx context.Context, in *pb.GetBoardRequest) (*pb.GetBoardReply, error) {
+	board, err := s.service.GetBoard(ctx)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get board.", err)
+		return nil, err
+	}
+
+	boardReply := &pb.GetBoardReply{}
+	for _, c := range board.Columns {
+		column := &pb.BoardColumn{Status: c.Status, WipLimit: int32(c.WIPLimit)}
+		for _, todo := range c.Cards {
+			column.Cards = append(column.Cards, todoToPb(todo))
+		}
+		boardReply.Columns = append(boardReply.Columns, column)
+	}
+	return boardReply, nil
+}
+
+// UpdateBoard update board handler.
+func (s *Server) UpdateBoard(ctx context.Context, in *pb.UpdateBoardRequest) (*pb.UpdateBoardReply, error) {
+	board := model.Board{}
+	for _, c := range in.GetColumns() {
+		board.Columns = append(board.Columns, model.BoardColumn{Status: c.GetStatus(), WIPLimit: int(c.GetWipLimit())})
+	}
+
+	err := s.service.UpdateBoard(ctx, board)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not update board.", err)
+		return nil, err
+	}
+	return &pb.UpdateBoardReply{}, nil
+}
+
+// MoveCard move card handler.
+func (s *Server) MoveCard(ctx context.Context, in *pb.MoveCardRequest) (*pb.MoveCardReply, error) {
+	move := model.CardMove{Status: in.GetStatus(), Position: int(in.GetPosition())}
+
+	err := s.service.MoveCard(ctx, in.GetTodoId(), move)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not move todo.", err)
+		return nil, err
+	}
+	return &pb.MoveCardReply{}, nil
+}
+
+// AddView add view handler.
+func (s *Server) AddView(ctx context.Context, in *pb.AddViewRequest) (*pb.AddViewReply, error) {
+	id, err := s.service.AddView(ctx, viewFromPb(in.GetView()))
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not add view.", err)
+		return nil, err
+	}
+	return &pb.AddViewReply{Id: id}, nil
+}
+
+// GetAllViews get all views handler.
+func (s *Server) GetAllViews(ctx context.Context, in *pb.GetAllViewsRequest) (*pb.GetAllViewsReply, error) {
+	views, err := s.service.GetViews(ctx)
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get views.", err)
+		return nil, err
+	}
+
+	viewsReply := &pb.GetAllViewsReply{}
+	for _, v := range views {
+		viewsReply.Views = append(viewsReply.Views, viewToPb(v))
+	}
+	return viewsReply, nil
+}
+
+// GetView get view handler.
+func (s *Server) GetView(ctx context.Context, in *pb.GetViewRequest) (*pb.GetViewReply, error) {
+	view, err := s.service.GetView(ctx, in.GetId())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get view.", err)
+		return nil, err
+	}
+	return &pb.GetViewReply{View: viewToPb(view)}, nil
+}
+
+// UpdateView update view handler.
+func (s *Server) UpdateView(ctx context.Context, in *pb.UpdateViewRequest) (*pb.UpdateViewReply, error) {
+	err := s.service.UpdateView(ctx, in.GetId(), viewFromPb(in.GetView()))
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not update view.", err)
+		return nil, err
+	}
+	return &pb.UpdateViewReply{}, nil
+}
+
+// DeleteView delete view handler.
+func (s *Server) DeleteView(ctx context.Context, in *pb.DeleteViewRequest) (*pb.DeleteViewReply, error) {
+	err := s.service.DeleteView(ctx, in.GetId())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not delete view.", err)
+		return nil, err
+	}
+	return &pb.DeleteViewReply{}, nil
+}
+
+// GetViewTodos get view todos handler.
+func (s *Server) GetViewTodos(ctx context.Context, in *pb.GetViewTodosRequest) (*pb.GetViewTodosReply, error) {
+	page, err := s.service.GetViewTodos(ctx, in.GetId(), int(in.GetLimit()), in.GetCursor())
+	if err != nil {
+		s.log.Errorf("%q: %v", "Could not get view todos.", err)
+		return nil, err
+	}
+
+	todosReply := &pb.GetViewTodosReply{NextCursor: page.NextCursor}
+	for _, todo := range page.Todos {
+		todosReply.Todos = append(todosReply.Todos, todoToPb(todo))
+	}
+	return todosReply, nil
+}
+
+func todoToPb(todo model.TodoItem) *pb.Todo {
+	return &pb.Todo{
+		Id:       todo.ID,
+		Name:     todo.Name,
+		Status:   todo.Status,
+		Date:     timestamppb.New(todo.Date),
+		AllDay:   todo.AllDay,
+		Position: int32(todo.Position),
+		Version:  int32(todo.Version),
+	}
+}
+
+// todoFromPb converts todo of request, missing date stays zero.
+func todoFromPb(t *pb.Todo) model.TodoItem {
+	todo := model.TodoItem{
+		ID:       t.GetId(),
+		Name:     t.GetName(),
+		Status:   t.GetStatus(),
+		AllDay:   t.GetAllDay(),
+		Position: int(t.GetPosition()),
+		Version:  int(t.GetVersion()),
+	}
+	if t.GetDate() != nil {
+		todo.Date = t.GetDate().AsTime()
+	}
+	return todo
+}
+
+func todoResultsToPb(results []model.TodoResult) []*pb.TodoResult {
+	arr := make([]*pb.TodoResult, 0, len(results))
+	for _, r := range results {
+		arr = append(arr, &pb.TodoResult{Id: r.ID, Version: int32(r.Version), Error: r.Error})
+	}
+	return arr
+}
+
+// todoFieldsToPb converts todo keeping only given fields, empty fields keep everything.
+func todoFieldsToPb(todo model.TodoItem, fields []string) *pb.Todo {
+	if len(fields) == 0 {
+		return todoToPb(todo)
+	}
+	t := &pb.Todo{}
+	for _, f := range fields {
+		switch f {
+		case "id":
+			t.Id = todo.ID
+		case "name":
+			t.Name = todo.Name
+		case "date":
+			t.Date = timestamppb.New(todo.Date)
+		case "allday":
+			t.AllDay = todo.AllDay
+		case "status":
+			t.Status = todo.Status
+		case "position":
+			t.Position = int32(todo.Position)
+		case "version":
+			t.Version = int32(todo.Version)
+		}
+	}
+	return t
+}
+
+// userToPb converts user keeping only given fields, empty fields keep everything.
+func userToPb(user model.User, fields []string) *pb.User {
+	if len(fields) == 0 {
+		fields = storage.UserFields
+	}
+	u := &pb.User{}
+	for _, f := range fields {
+		switch f {
+		case "id":
+			u.Id = user.ID
+		case "username":
+			u.UserName = user.UserName
+		case "firstname":
+			u.FirstName = user.FirstName
+		case "lastname":
+			u.LastName = user.LastName
+		case "location":
+			u.Location = user.Location.String()
+		}
+	}
+	return u
+}
+
+// maskFields converts field mask paths to storage field names.
+// Paths are matched case-insensitively, so both "UserName" and "username" work.
+func maskFields(mask *fieldmaskpb.FieldMask, allowed []string) ([]string, error) {
+	fields, err := storage.ParseFields(strings.Join(mask.GetPaths(), ","), allowed)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Invalid field mask.", err, model.ErrBadRequest)
+	}
+	return fields, nil
+}
+
+func viewToPb(view model.View) *pb.View {
+	v := &pb.View{
+		Id:     view.ID,
+		Name:   view.Name,
+		Status: view.Status,
+		Query:  view.Query,
+		Sort:   view.Sort,
+	}
+	if view.FromDate != nil {
+		v.FromDate = timestamppb.New(*view.FromDate)
+	}
+	if view.ToDate != nil {
+		v.ToDate = timestamppb.New(*view.ToDate)
+	}
+	return v
+}
+
+func viewFromPb(in *pb.View) model.View {
+	view := model.View{
+		Name:   in.GetName(),
+		Status: in.GetStatus(),
+		Query:  in.GetQuery(),
+		Sort:   in.GetSort(),
+	}
+	if in.GetFromDate() != nil {
+		fromDate := in.GetFromDate().AsTime()
+		view.FromDate = &fromDate
+	}
+	if in.GetToDate() != nil {
+		toDate := in.GetToDate().AsTime()
+		view.ToDate = &toDate
+	}
+	return view
+}
@@ -2,6 +2,7 @@ package grpcsrv
 
 import (
 	"context"
+	"errors"
 	"todo/config"
 	"todo/model"
 	"todo/service"
@@ -53,3 +54,36 @@ func (a *AuthMD) authorize(ctx context.Context) (context.Context, error) {
 
 	return ctx, nil
 }
+
+// ErrorInterceptor converts application errors returned by handlers to grpc status errors.
+func ErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+}
+
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, model.ErrOperational):
+		return codes.Internal
+	case errors.Is(err, model.ErrBadRequest):
+		return codes.InvalidArgument
+	case errors.Is(err, model.ErrNotFound):
+		return codes.PermissionDenied
+	case errors.Is(err, model.ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(err, model.ErrVersionConflict), errors.Is(err, model.ErrUserHasTodos):
+		return codes.FailedPrecondition
+	case errors.Is(err, model.ErrConflict):
+		return codes.AlreadyExists
+	default:
+		return codes.Unknown
+	}
+}
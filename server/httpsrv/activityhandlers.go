@@ -0,0 +1,66 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"todo/model"
+	"todo/storage"
+)
+
+// activity handlers.
+func (t *Server) getActivityHandler(w http.ResponseWriter, r *http.Request) {
+	filter := storage.ActivityFilter{}
+	for _, val := range r.URL.Query()["type"] {
+		for _, activityType := range strings.Split(val, ",") {
+			if activityType != "" {
+				filter.Types = append(filter.Types, activityType)
+			}
+		}
+	}
+
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getActivityHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	filter.Limit = limit
+
+	page, err := t.service.GetActivity(r.Context(), filter, r.URL.Query().Get("cursor"))
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getActivityHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getActivityHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func getLimit(r *http.Request) (int, error) {
+	val := r.URL.Query().Get("limit")
+	if val == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(val)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid limit %q", val)
+	}
+	return limit, nil
+}
+
+// setNextLink sets Link header pointing to the next page of the listing.
+func setNextLink(w http.ResponseWriter, r *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+	values := r.URL.Query()
+	values.Set("cursor", cursor)
+	next := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+	w.Header().Set("Link", "<"+next.String()+">; rel=\"next\"")
+}
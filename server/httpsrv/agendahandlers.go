@@ -0,0 +1,24 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"todo/model"
+)
+
+// agenda handlers.
+func (t *Server) getAgendaHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	agenda, err := t.service.GetAgenda(r.Context(), q.Get("from"), q.Get("to"), q.Get("tz"))
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getAgendaHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(agenda); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAgendaHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
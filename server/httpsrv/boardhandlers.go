@@ -0,0 +1,51 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"todo/model"
+)
+
+// board handlers.
+func (t *Server) getBoardHandler(w http.ResponseWriter, r *http.Request) {
+	board, err := t.service.GetBoard(r.Context())
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getBoardHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(board); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getBoardHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) updateBoardHandler(w http.ResponseWriter, r *http.Request) {
+	board := model.Board{}
+	if err := json.NewDecoder(r.Body).Decode(&board); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in updateBoardHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	if err := t.service.UpdateBoard(r.Context(), board); err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in updateBoardHandler.", err), w)
+		return
+	}
+}
+
+func (t *Server) moveCardHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "todoId")
+	move := model.CardMove{}
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in moveCardHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	if err := t.service.MoveCard(r.Context(), id, move); err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in moveCardHandler.", err), w)
+		return
+	}
+}
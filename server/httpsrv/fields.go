@@ -0,0 +1,43 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// encodeFields writes v as json keeping only given fields.
+// v is an object or a list of objects, empty fields keep everything.
+func encodeFields(w io.Writer, v interface{}, fields []string) error {
+	if len(fields) == 0 {
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for k := range list {
+			list[k] = pickFields(list[k], fields)
+		}
+		return json.NewEncoder(w).Encode(list)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(pickFields(object, fields))
+}
+
+func pickFields(object map[string]json.RawMessage, fields []string) map[string]json.RawMessage {
+	picked := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if val, ok := object[f]; ok {
+			picked[f] = val
+		}
+	}
+	return picked
+}
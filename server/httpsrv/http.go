@@ -33,6 +33,12 @@ func NewHTTPServer(service service.Handlers, c *config.Config, l logger.Logger)
 	s := chi.NewRouter()
 	s.Get("/todos", Chain(t.getAllItemsHandler, t.SetContentType(), t.Authorize(), t.Log()))
 	s.Post("/todos", Chain(t.addItemHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Post("/todos:batch", Chain(t.batchItemsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/todos/search", Chain(t.searchItemsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/todos/today", Chain(t.smartListHandler(model.ListToday), t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/todos/upcoming", Chain(t.smartListHandler(model.ListUpcoming), t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/todos/overdue", Chain(t.smartListHandler(model.ListOverdue), t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/todos/someday", Chain(t.smartListHandler(model.ListSomeday), t.SetContentType(), t.Authorize(), t.Log()))
 	s.Get("/todos/{todoId}", Chain(t.getItemHandler, t.SetContentType(), t.Authorize(), t.Log()))
 	s.Delete("/todos/{todoId}", Chain(t.deleteItemHandler, t.SetContentType(), t.Authorize(), t.Log()))
 	s.Put("/todos/{todoId}", Chain(t.updateItemHandler, t.SetContentType(), t.Authorize(), t.Log()))
@@ -42,6 +48,22 @@ func NewHTTPServer(service service.Handlers, c *config.Config, l logger.Logger)
 	s.Delete("/users/{userId}", Chain(t.deleteUserHandler, t.SetContentType(), t.Authorize(), t.Log()))
 	s.Put("/users/{userId}", Chain(t.updateUserHandler, t.SetContentType(), t.Authorize(), t.Log()))
 	s.Post("/user/login", Chain(t.loginUserHandler, t.SetContentType(), t.Log()))
+	s.Get("/activity", Chain(t.getActivityHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/stats", Chain(t.getStatsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/agenda", Chain(t.getAgendaHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/notifications", Chain(t.getNotificationsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/notifications/unread", Chain(t.getUnreadCountHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Post("/notifications/read", Chain(t.readAllNotificationsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Post("/notifications/{notificationId}/read", Chain(t.readNotificationHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/board", Chain(t.getBoardHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Put("/board", Chain(t.updateBoardHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Post("/board/cards/{todoId}/move", Chain(t.moveCardHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/views", Chain(t.getAllViewsHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Post("/views", Chain(t.addViewHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/views/{viewId}", Chain(t.getViewHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Put("/views/{viewId}", Chain(t.updateViewHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Delete("/views/{viewId}", Chain(t.deleteViewHandler, t.SetContentType(), t.Authorize(), t.Log()))
+	s.Get("/views/{viewId}/todos", Chain(t.getViewItemsHandler, t.SetContentType(), t.Authorize(), t.Log()))
 
 	s.Handle("/metrics", promhttp.Handler())
 
@@ -64,6 +86,10 @@ func (t *Server) handleError(err error, w http.ResponseWriter) {
 		status = http.StatusForbidden
 	case errors.Is(err, model.ErrUnauthorized):
 		status = http.StatusUnauthorized
+	case errors.Is(err, model.ErrVersionConflict):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, model.ErrUserHasTodos), errors.Is(err, model.ErrConflict):
+		status = http.StatusConflict
 	default:
 		status = http.StatusBadRequest
 	}
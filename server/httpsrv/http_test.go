@@ -2,10 +2,14 @@ package httpsrv
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,6 +29,9 @@ func TestServerWithMock(t *testing.T) {
 
 	c := conf.New()
 	m := mockstore.NewMockStorage(ctrl)
+	m.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(tx storage.Storage) error) error {
+		return fn(m)
+	}).AnyTimes()
 	s := service.NewService(m, c)
 
 	server := NewHTTPServer(s, c, logger.New(ioutil.Discard))
@@ -61,9 +68,9 @@ func TestServerWithMock(t *testing.T) {
 
 	t.Run("test authenticate user", func(t *testing.T) {
 		users := []model.User{user}
-		m.EXPECT().GetAllUsers(storage.UserFilter{UserName: credentials.UserName}).Return(users, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{UserName: credentials.UserName}).Return(users, nil)
 
-		_, err := server.service.AuthenticateUser(credentials)
+		_, err := server.service.AuthenticateUser(context.Background(), credentials)
 		assert.NoError(t, err)
 	})
 
@@ -83,7 +90,7 @@ func TestServerWithMock(t *testing.T) {
 
 	t.Run("test login user", func(t *testing.T) {
 		users := []model.User{user}
-		m.EXPECT().GetAllUsers(storage.UserFilter{UserName: credentials.UserName}).Return(users, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{UserName: credentials.UserName}).Return(users, nil)
 
 		credentialsJSON, err := json.Marshal(&credentials)
 		assert.NoError(t, err)
@@ -139,8 +146,8 @@ func TestServerWithMock(t *testing.T) {
 	*/
 
 	t.Run("get user", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
 
 		userJSON, err := json.Marshal(&user)
 		assert.NoError(t, err)
@@ -158,8 +165,8 @@ func TestServerWithMock(t *testing.T) {
 
 	t.Run("get all users", func(t *testing.T) {
 		users := []model.User{user}
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetAllUsers(storage.UserFilter{}).Return(users, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{Limit: 51}).Return(users, nil)
 
 		userJSON, err := json.Marshal(&users)
 		assert.NoError(t, err)
@@ -177,8 +184,8 @@ func TestServerWithMock(t *testing.T) {
 
 	t.Run("get all users filtered", func(t *testing.T) {
 		users := []model.User{user}
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetAllUsers(storage.UserFilter{UserName: user.UserName}).Return(users, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{UserName: user.UserName, Limit: 51}).Return(users, nil)
 
 		userJSON, err := json.Marshal(&users)
 		assert.NoError(t, err)
@@ -194,6 +201,51 @@ func TestServerWithMock(t *testing.T) {
 		assert.JSONEq(t, string(userJSON), response.Body.String())
 	})
 
+	t.Run("get user with sparse fieldset", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{IDs: []string{user.ID}, Fields: []string{"id", "username"}}).
+			Return([]model.User{{ID: user.ID, UserName: user.UserName}}, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/users/"+user.ID+"?fields=id,username", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{"id":"`+user.ID+`","username":"`+user.UserName+`"}`, response.Body.String())
+	})
+
+	t.Run("search users", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{
+			Query:  "ro",
+			Fields: storage.PublicUserFields,
+			Limit:  51,
+		}).Return([]model.User{{ID: user.ID, UserName: user.UserName, FirstName: user.FirstName, LastName: user.LastName}}, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/users?q=ro", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `[{"id":"`+user.ID+`","username":"Roxy","firstname":"Roxy","lastname":"Proxy"}]`, response.Body.String())
+	})
+
+	t.Run("search users with private field", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/users?q=ro&fields=id,location", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
 	t.Run("update user", func(t *testing.T) {
 		newuser := model.User{
 			ID:        user.ID,
@@ -202,9 +254,12 @@ func TestServerWithMock(t *testing.T) {
 			LastName:  user.LastName,
 			Location:  user.Location,
 		}
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().UpdateUser(newuser).Return(nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().UpdateUser(gomock.Any(), newuser).Return(nil)
+		payload, err := json.Marshal(newuser)
+		assert.NoError(t, err)
+		m.EXPECT().AddEvent(gomock.Any(), model.Event{Type: model.EventUserUpdated, UserID: user.ID, Payload: payload}).Return("1", nil)
 
 		userJSON, err := json.Marshal(&newuser)
 		assert.NoError(t, err)
@@ -219,10 +274,36 @@ func TestServerWithMock(t *testing.T) {
 		assert.Equal(t, response.Header().Get("Content-Type"), "application/json")
 	})
 
+	t.Run("update user with taken username", func(t *testing.T) {
+		newuser := model.User{
+			ID:       user.ID,
+			UserName: "roxy2",
+			Location: user.Location,
+		}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().UpdateUser(gomock.Any(), newuser).Return(fmt.Errorf("username %q already exists: %w", newuser.UserName, model.ErrConflict))
+
+		userJSON, err := json.Marshal(&newuser)
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, "/users/"+user.ID, bytes.NewBuffer(userJSON))
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
 	t.Run("delete user", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().DeleteUser(user.ID).Return(nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().LockUsers(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Limit: 1, Fields: []string{"id"}}).Return([]model.TodoItem{}, nil)
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID).Return(nil)
+		payload, err := json.Marshal(user)
+		assert.NoError(t, err)
+		m.EXPECT().AddEvent(gomock.Any(), model.Event{Type: model.EventUserDeleted, UserID: user.ID, Payload: payload}).Return("2", nil)
 
 		request, err := http.NewRequest(http.MethodDelete, "/users/"+user.ID, nil)
 		assert.NoError(t, err)
@@ -234,9 +315,61 @@ func TestServerWithMock(t *testing.T) {
 		assert.Equal(t, response.Header().Get("Content-Type"), "application/json")
 	})
 
+	t.Run("delete user with todos", func(t *testing.T) {
+		m.EXPECT().LockUsers(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Limit: 1, Fields: []string{"id"}}).Return([]model.TodoItem{{ID: "1"}}, nil)
+
+		request, err := http.NewRequest(http.MethodDelete, "/users/"+user.ID, nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusConflict, response.Code)
+	})
+
+	t.Run("delete user reassigning todos", func(t *testing.T) {
+		heir := model.User{ID: "7ba7b810-9dad-11d1-80b4-00c04fd430c8", UserName: "Heir"}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetUser(gomock.Any(), heir.ID).Return(heir, nil)
+		m.EXPECT().LockUsers(gomock.Any(), user.ID, heir.ID).Return(nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Fields: []string{"id"}}).Return([]model.TodoItem{{ID: "1"}, {ID: "2"}}, nil)
+		m.EXPECT().ReassignItems(gomock.Any(), user.ID, heir.ID).Return(nil)
+		m.EXPECT().AddNotification(gomock.Any(), model.Notification{
+			Type:    model.NotificationAssignment,
+			UserID:  heir.ID,
+			ActorID: user.ID,
+			Message: "2 todos of Roxy were reassigned to you",
+		}).Return("4", nil)
+		m.EXPECT().DeleteUser(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("3", nil)
+
+		request, err := http.NewRequest(http.MethodDelete, "/users/"+user.ID+"?todos=reassign&reassignto="+heir.ID, nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("delete user with unknown todos policy", func(t *testing.T) {
+		m.EXPECT().LockUsers(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+
+		request, err := http.NewRequest(http.MethodDelete, "/users/"+user.ID+"?todos=archive", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
 	t.Run("get not existing item", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetItem("123").Return(model.TodoItem{}, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetItem(gomock.Any(), "123").Return(model.TodoItem{}, nil)
 
 		request, err := http.NewRequest(http.MethodGet, "/todos/123", nil)
 		assert.NoError(t, err)
@@ -247,6 +380,150 @@ func TestServerWithMock(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, response.Code)
 	})
 
+	t.Run("get item with etag", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetItem(gomock.Any(), "1").Return(model.TodoItem{ID: "1", Name: "a", Version: 3, UserID: user.ID}, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/todos/1", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `"3"`, response.Header().Get("ETag"))
+	})
+
+	t.Run("get item with sparse fieldset", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, IDs: []string{"1"}, Fields: []string{"name", "version"}}).
+			Return([]model.TodoItem{{ID: "1", Name: "a", Version: 3}}, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/todos/1?fields=name", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `"3"`, response.Header().Get("ETag"))
+		assert.JSONEq(t, `{"name":"a"}`, response.Body.String())
+	})
+
+	t.Run("get item of another user with sparse fieldset", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, IDs: []string{"2"}, Fields: []string{"name", "version"}}).
+			Return([]model.TodoItem{}, nil)
+
+		request, err := http.NewRequest(http.MethodGet, "/todos/2?fields=name", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("update item with stale version", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetItem(gomock.Any(), "1").Return(model.TodoItem{ID: "1", Name: "a", Version: 4, UserID: user.ID}, nil)
+
+		todoJSON, err := json.Marshal(model.TodoItem{Name: "b"})
+		assert.NoError(t, err)
+		request, err := http.NewRequest(http.MethodPut, "/todos/1", bytes.NewBuffer(todoJSON))
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", `"3"`)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+	})
+
+	t.Run("update item with invalid If-Match", func(t *testing.T) {
+		todoJSON, err := json.Marshal(model.TodoItem{Name: "b"})
+		assert.NoError(t, err)
+		request, err := http.NewRequest(http.MethodPut, "/todos/1", bytes.NewBuffer(todoJSON))
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", "three")
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("delete item with current version", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		todo := model.TodoItem{ID: "1", Name: "a", Version: 4, UserID: user.ID}
+		m.EXPECT().GetItem(gomock.Any(), "1").Return(todo, nil)
+		m.EXPECT().DeleteItem(gomock.Any(), "1").Return(nil)
+		m.EXPECT().AddActivity(gomock.Any(), model.Activity{
+			Type:     model.ActivityTodoDeleted,
+			UserID:   user.ID,
+			TodoID:   "1",
+			TodoName: "a",
+		}).Return("5", nil)
+		payload, err := json.Marshal(todo)
+		assert.NoError(t, err)
+		m.EXPECT().AddEvent(gomock.Any(), model.Event{Type: model.EventTodoDeleted, UserID: user.ID, Payload: payload}).Return("4", nil)
+
+		request, err := http.NewRequest(http.MethodDelete, "/todos/1", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", `"4"`)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("update item with weak If-Match", func(t *testing.T) {
+		todoJSON, err := json.Marshal(model.TodoItem{Name: "b"})
+		assert.NoError(t, err)
+		request, err := http.NewRequest(http.MethodPut, "/todos/1", bytes.NewBuffer(todoJSON))
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", `W/"4"`)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code, "weak tags never match")
+	})
+
+	t.Run("delete item with If-Match list", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		todo := model.TodoItem{ID: "1", Name: "a", Version: 4, UserID: user.ID}
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, IDs: []string{"1"}, Fields: storage.WithField([]string{"version"}, "version")}).Return([]model.TodoItem{{ID: "1", Version: 4}}, nil)
+		m.EXPECT().GetItem(gomock.Any(), "1").Return(todo, nil)
+		m.EXPECT().DeleteItem(gomock.Any(), "1").Return(nil)
+		m.EXPECT().AddActivity(gomock.Any(), gomock.Any()).Return("5", nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("4", nil)
+
+		request, err := http.NewRequest(http.MethodDelete, "/todos/1", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", `W/"3", "2", "4"`)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("delete item with stale If-Match list", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, IDs: []string{"1"}, Fields: storage.WithField([]string{"version"}, "version")}).Return([]model.TodoItem{{ID: "1", Version: 4}}, nil)
+
+		request, err := http.NewRequest(http.MethodDelete, "/todos/1", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		request.Header.Set("If-Match", `"2", "3"`)
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+	})
+
 	t.Run("get all items unathorized", func(t *testing.T) {
 		response := httptest.NewRecorder()
 		request, err := http.NewRequest(http.MethodGet, "/todos", nil)
@@ -257,8 +534,8 @@ func TestServerWithMock(t *testing.T) {
 	})
 
 	t.Run("get all items (empty)", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
-		m.EXPECT().GetAllItems(storage.TodoFilter{UserID: user.ID}).Return([]model.TodoItem{}, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Limit: 51}).Return([]model.TodoItem{}, nil)
 
 		response := httptest.NewRecorder()
 		request, err := http.NewRequest(http.MethodGet, "/todos", nil)
@@ -272,7 +549,7 @@ func TestServerWithMock(t *testing.T) {
 	})
 
 	t.Run("get filtered items", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
 		todoItems := []model.TodoItem{
 			{ID: "123", Name: "test1", Date: time.Now(), Status: "done"},
 			{ID: "124", Name: "test2", Date: time.Now(), Status: "done"},
@@ -280,9 +557,9 @@ func TestServerWithMock(t *testing.T) {
 		todoItemsJSON, err := json.Marshal(&todoItems)
 		assert.NoError(t, err)
 
-		filter := storage.TodoFilter{UserID: user.ID, Status: "done"}
+		filter := storage.TodoFilter{UserID: user.ID, Status: "done", Limit: 51}
 
-		m.EXPECT().GetAllItems(filter).Return(todoItems, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), filter).Return(todoItems, nil)
 
 		response := httptest.NewRecorder()
 		request, err := http.NewRequest(http.MethodGet, "/todos?status=done", nil)
@@ -295,11 +572,159 @@ func TestServerWithMock(t *testing.T) {
 		assert.JSONEq(t, string(todoItemsJSON), response.Body.String())
 	})
 
+	t.Run("get items paginated", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+		todoItems := []model.TodoItem{
+			{ID: "123", Name: "test1", Date: date, Status: "new"},
+			{ID: "124", Name: "test2", Date: date, Status: "new"},
+		}
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Limit: 2}).Return(todoItems, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?limit=1", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		firstPageJSON, err := json.Marshal(todoItems[:1])
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(firstPageJSON), response.Body.String())
+
+		link := response.Header().Get("Link")
+		assert.True(t, strings.HasPrefix(link, "</todos?"), link)
+		assert.True(t, strings.HasSuffix(link, `>; rel="next"`), link)
+
+		next, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", next.Query().Get("limit"))
+
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{
+			UserID: user.ID,
+			After:  &model.TodoItem{ID: "123", Name: "test1", Date: date, Status: "new"},
+			Limit:  2,
+		}).Return(todoItems[1:], nil)
+
+		response = httptest.NewRecorder()
+		request, err = http.NewRequest(http.MethodGet, next.String(), nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, response.Header().Get("Link"))
+
+		secondPageJSON, err := json.Marshal(todoItems[1:])
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(secondPageJSON), response.Body.String())
+	})
+
+	t.Run("get sorted items", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{
+			UserID: user.ID,
+			Sort:   []storage.SortField{{Field: "status", Desc: true}, {Field: "name"}},
+			Limit:  51,
+		}).Return([]model.TodoItem{}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?sort=-status,name", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get items sorted by unknown field", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?sort=-password", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get items with sparse fieldset", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{
+			UserID: user.ID,
+			Fields: []string{"id", "name", "status"},
+			Limit:  51,
+		}).Return([]model.TodoItem{{ID: "123", Name: "test1", Status: "new"}}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?fields=id,name,status", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `[{"id":"123","name":"test1","status":"new"}]`, response.Body.String())
+	})
+
+	t.Run("get items with unknown field", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?fields=id,userid", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get items with invalid cursor", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?cursor=bad", nil)
+		assert.NoError(t, err)
+
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get items with invalid query", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?q="+url.QueryEscape("status:done AND"), nil)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		assert.NoError(t, err)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get items with deeply nested query", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos?q="+url.QueryEscape(strings.Repeat("(", 1000)), nil)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		assert.NoError(t, err)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
 	t.Run("add new item", func(t *testing.T) {
-		m.EXPECT().GetUser(user.ID).Return(user, nil)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
 		todo := model.TodoItem{Name: "test1", UserID: user.ID}
 		todoID := model.TodoID{ID: "123"}
-		m.EXPECT().AddItem(todo).Return("123", nil)
+		m.EXPECT().AddItem(gomock.Any(), todo).Return("123", nil)
+		m.EXPECT().AddActivity(gomock.Any(), model.Activity{
+			Type:     model.ActivityTodoCreated,
+			UserID:   user.ID,
+			TodoID:   "123",
+			TodoName: "test1",
+		}).Return("1", nil)
+		stored := model.TodoItem{ID: "123", Name: "test1", Status: "new", Version: 1, UserID: user.ID}
+		m.EXPECT().GetItem(gomock.Any(), "123").Return(stored, nil)
+		payload, err := json.Marshal(stored)
+		assert.NoError(t, err)
+		m.EXPECT().AddEvent(gomock.Any(), model.Event{Type: model.EventTodoCreated, UserID: user.ID, Payload: payload}).Return("5", nil)
 
 		todoJSON, err := json.Marshal(&todo)
 		assert.NoError(t, err)
@@ -317,6 +742,611 @@ func TestServerWithMock(t *testing.T) {
 		assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
 		assert.JSONEq(t, string(todoIDJSON), response.Body.String())
 	})
+
+	t.Run("get activity", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		activities := []model.Activity{
+			{ID: "2", Type: model.ActivityTodoCompleted, UserID: user.ID, TodoID: "123", TodoName: "test1", Date: time.Now().UTC()},
+			{ID: "1", Type: model.ActivityTodoCreated, UserID: user.ID, TodoID: "123", TodoName: "test1", Date: time.Now().UTC()},
+		}
+		filter := storage.ActivityFilter{
+			UserID: user.ID,
+			Types:  []string{model.ActivityTodoCreated, model.ActivityTodoCompleted},
+			Limit:  2,
+		}
+		m.EXPECT().GetAllActivities(gomock.Any(), filter).Return(activities, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/activity?type=todo.created,todo.completed&limit=1", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		page := model.ActivityPage{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&page))
+		assert.Len(t, page.Activities, 1)
+		assert.Equal(t, "2", page.Activities[0].ID)
+		assert.NotEmpty(t, page.NextCursor)
+	})
+
+	t.Run("add new item with mention", func(t *testing.T) {
+		mentioned := model.User{ID: "6ba7b811-9dad-11d1-80b4-00c04fd430c8", UserName: "Bob"}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		todo := model.TodoItem{Name: "call @Bob", UserID: user.ID}
+		m.EXPECT().AddItem(gomock.Any(), todo).Return("125", nil)
+		m.EXPECT().AddActivity(gomock.Any(), gomock.Any()).Return("3", nil)
+		m.EXPECT().GetItem(gomock.Any(), "125").Return(model.TodoItem{ID: "125", Name: "call @Bob", UserID: user.ID}, nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("6", nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{UserName: "Bob"}).Return([]model.User{mentioned}, nil)
+		m.EXPECT().AddNotification(gomock.Any(), model.Notification{
+			Type:    model.NotificationMention,
+			UserID:  mentioned.ID,
+			ActorID: user.ID,
+			TodoID:  "125",
+			Message: `Roxy mentioned you in "call @Bob"`,
+		}).Return("1", nil)
+
+		todoJSON, err := json.Marshal(&todo)
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(todoJSON))
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("add new item with mention in different case", func(t *testing.T) {
+		mentioned := model.User{ID: "6ba7b811-9dad-11d1-80b4-00c04fd430c8", UserName: "Bob"}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		todo := model.TodoItem{Name: "call @Bob @bob", UserID: user.ID}
+		m.EXPECT().AddItem(gomock.Any(), todo).Return("126", nil)
+		m.EXPECT().AddActivity(gomock.Any(), gomock.Any()).Return("3", nil)
+		m.EXPECT().GetItem(gomock.Any(), "126").Return(model.TodoItem{ID: "126", Name: "call @Bob @bob", UserID: user.ID}, nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("6", nil)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{UserName: "Bob"}).Return([]model.User{mentioned}, nil)
+		m.EXPECT().AddNotification(gomock.Any(), model.Notification{
+			Type:    model.NotificationMention,
+			UserID:  mentioned.ID,
+			ActorID: user.ID,
+			TodoID:  "126",
+			Message: `Roxy mentioned you in "call @Bob @bob"`,
+		}).Return("1", nil)
+
+		todoJSON, err := json.Marshal(&todo)
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(todoJSON))
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("batch items", func(t *testing.T) {
+		first, second, missing := "3f1d7c9a-8b2e-4c6d-9e0f-1a2b3c4d5e6f", "6a7b8c9d-0e1f-4a2b-8c3d-4e5f6a7b8c9d", "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a"
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, IDs: []string{first, second, second, missing}}).Return([]model.TodoItem{
+			{ID: first, Name: "a", Status: "new", Version: 3, UserID: user.ID},
+			{ID: second, Name: "b", Status: "new", Position: 4, Version: 1, UserID: user.ID},
+		}, nil)
+		m.EXPECT().AddItems(gomock.Any(), []model.TodoItem{{Name: "c", UserID: user.ID}}).Return([]storage.ItemResult{{ID: "10", Version: 1}}, nil)
+		m.EXPECT().UpdateItems(gomock.Any(), []model.TodoItem{{ID: second, Name: "b", Status: "done", Position: 4, Version: 1, UserID: user.ID}}).Return([]storage.ItemResult{{ID: second, Version: 2}}, nil)
+		m.EXPECT().AddActivity(gomock.Any(), model.Activity{
+			Type:     model.ActivityTodoCreated,
+			UserID:   user.ID,
+			TodoID:   "10",
+			TodoName: "c",
+		}).Return("6", nil)
+		m.EXPECT().AddActivity(gomock.Any(), model.Activity{
+			Type:     model.ActivityTodoCompleted,
+			UserID:   user.ID,
+			TodoID:   second,
+			TodoName: "b",
+		}).Return("7", nil)
+		m.EXPECT().GetItem(gomock.Any(), "10").Return(model.TodoItem{ID: "10", Name: "c", Status: "new", Version: 1, UserID: user.ID}, nil)
+		m.EXPECT().GetItem(gomock.Any(), second).Return(model.TodoItem{ID: second, Name: "b", Status: "done", Position: 4, Version: 2, UserID: user.ID}, nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("8", nil).Times(2)
+
+		batchJSON, err := json.Marshal(model.TodoBatch{
+			Add:    []model.TodoItem{{Name: "c"}},
+			Update: []model.TodoItem{{ID: first, Name: "a", Version: 2}, {ID: second, Name: "b", Status: "done", Version: 1}},
+			Delete: []model.TodoItem{{ID: second}, {ID: missing}, {ID: "bad"}},
+		})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/todos:batch", bytes.NewBuffer(batchJSON))
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, `{
+			"add": [{"id": "10", "version": 1}],
+			"update": [{"id": "`+first+`", "error": "version conflict"}, {"id": "`+second+`", "version": 2}],
+			"delete": [{"id": "`+second+`", "error": "duplicate id"}, {"id": "`+missing+`", "error": "not found"}, {"id": "bad", "error": "not found"}]
+		}`, response.Body.String())
+	})
+
+	t.Run("batch items too large", func(t *testing.T) {
+		batchJSON, err := json.Marshal(model.TodoBatch{Delete: make([]model.TodoItem, 1001)})
+		assert.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPost, "/todos:batch", bytes.NewBuffer(batchJSON))
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		response := httptest.NewRecorder()
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("remind due todos", func(t *testing.T) {
+		due := time.Now().Add(30 * time.Minute).In(l)
+		m.EXPECT().GetAllUsers(gomock.Any(), storage.UserFilter{Fields: []string{"id"}}).Return([]model.User{{ID: user.ID}}, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter storage.TodoFilter) ([]model.TodoItem, error) {
+			assert.Equal(t, user.ID, filter.UserID)
+			assert.True(t, filter.ToDate.Sub(*filter.FromDate) == c.ReminderLead, "todos due within reminder lead")
+			return []model.TodoItem{
+				{ID: "1", Name: "call", Date: due, Status: "new", UserID: user.ID},
+				{ID: "2", Name: "done", Date: due, Status: "done", UserID: user.ID},
+				{ID: "3", Name: "reminded", Date: due, Status: "new", UserID: user.ID},
+			}, nil
+		})
+		m.EXPECT().GetAllNotifications(gomock.Any(), storage.NotificationFilter{UserID: user.ID, Type: model.NotificationReminder, TodoID: "1", Limit: 1}).Return([]model.Notification{}, nil)
+		m.EXPECT().GetAllNotifications(gomock.Any(), storage.NotificationFilter{UserID: user.ID, Type: model.NotificationReminder, TodoID: "3", Limit: 1}).Return([]model.Notification{{ID: "9"}}, nil)
+		m.EXPECT().AddNotification(gomock.Any(), model.Notification{
+			Type:    model.NotificationReminder,
+			UserID:  user.ID,
+			TodoID:  "1",
+			Message: `"call" is due at ` + due.Format("2006-01-02 15:04"),
+		}).Return("10", nil)
+
+		assert.NoError(t, server.service.RemindDueTodos(context.Background()))
+	})
+
+	t.Run("get notifications", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		notifications := []model.Notification{{ID: "1", Type: model.NotificationMention, UserID: user.ID, Message: "hi"}}
+		m.EXPECT().GetAllNotifications(gomock.Any(), storage.NotificationFilter{UserID: user.ID, UnreadOnly: true, Limit: 51}).Return(notifications, nil)
+		m.EXPECT().CountUnreadNotifications(gomock.Any(), user.ID).Return(1, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/notifications?unread=true", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		page := model.NotificationPage{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&page))
+		assert.Len(t, page.Notifications, 1)
+		assert.Equal(t, 1, page.Unread)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("read notification of another user", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetNotification(gomock.Any(), "1").Return(model.Notification{ID: "1", UserID: "someone"}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/notifications/1/read", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("read notification", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetNotification(gomock.Any(), "1").Return(model.Notification{ID: "1", UserID: user.ID}, nil)
+		m.EXPECT().MarkNotificationRead(gomock.Any(), "1").Return(nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/notifications/1/read", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("get board", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetBoard(gomock.Any(), user.ID).Return(model.Board{UserID: user.ID, Columns: []model.BoardColumn{
+			{Status: "new"}, {Status: "doing", WIPLimit: 1}, {Status: "done"},
+		}}, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID}).Return([]model.TodoItem{
+			{ID: "1", Name: "a", Status: "doing"},
+			{ID: "2", Name: "b", Status: "new", Position: 1},
+			{ID: "3", Name: "c", Status: "new"},
+			{ID: "4", Name: "d", Status: "blocked"},
+		}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/board", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		board := model.Board{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&board))
+		assert.Len(t, board.Columns, 4)
+		assert.Equal(t, "new", board.Columns[0].Status)
+		assert.Equal(t, "3", board.Columns[0].Cards[0].ID)
+		assert.Equal(t, "2", board.Columns[0].Cards[1].ID)
+		assert.Equal(t, 1, board.Columns[1].WIPLimit)
+		assert.Equal(t, "blocked", board.Columns[3].Status)
+	})
+
+	t.Run("move card over WIP limit", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetItem(gomock.Any(), "2").Return(model.TodoItem{ID: "2", Status: "new", UserID: user.ID}, nil)
+		m.EXPECT().LockBoard(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().GetBoard(gomock.Any(), user.ID).Return(model.Board{UserID: user.ID, Columns: []model.BoardColumn{
+			{Status: "new"}, {Status: "doing", WIPLimit: 1},
+		}}, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Status: "doing"}).Return([]model.TodoItem{
+			{ID: "1", Status: "doing", UserID: user.ID},
+		}, nil)
+
+		moveJSON, err := json.Marshal(model.CardMove{Status: "doing"})
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/board/cards/2/move", bytes.NewBuffer(moveJSON))
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("move card", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetItem(gomock.Any(), "2").Return(model.TodoItem{ID: "2", Name: "b", Status: "new", UserID: user.ID}, nil)
+		m.EXPECT().LockBoard(gomock.Any(), user.ID).Return(nil)
+		m.EXPECT().GetBoard(gomock.Any(), user.ID).Return(model.Board{UserID: user.ID}, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{UserID: user.ID, Status: "done"}).Return([]model.TodoItem{
+			{ID: "1", Name: "a", Status: "done", UserID: user.ID},
+		}, nil)
+		m.EXPECT().UpdateItem(gomock.Any(), model.TodoItem{ID: "2", Name: "b", Status: "done", UserID: user.ID}).Return(nil)
+		m.EXPECT().UpdateItemPositions(gomock.Any(), []model.TodoItem{{ID: "1", Name: "a", Status: "done", Position: 1, UserID: user.ID}}).Return(nil)
+		m.EXPECT().AddActivity(gomock.Any(), model.Activity{
+			Type:     model.ActivityTodoCompleted,
+			UserID:   user.ID,
+			TodoID:   "2",
+			TodoName: "b",
+		}).Return("4", nil)
+		m.EXPECT().GetItem(gomock.Any(), "2").Return(model.TodoItem{ID: "2", Name: "b", Status: "done", Version: 2, UserID: user.ID}, nil)
+		m.EXPECT().AddEvent(gomock.Any(), gomock.Any()).Return("7", nil)
+
+		moveJSON, err := json.Marshal(model.CardMove{Status: "done", Position: 0})
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/board/cards/2/move", bytes.NewBuffer(moveJSON))
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("search items", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		results := []model.TodoSearchResult{{Todo: model.TodoItem{ID: "1", Name: "Pay invoice"}, Rank: 0.5, Snippet: "Pay <b>invoice</b>"}}
+		m.EXPECT().SearchItems(gomock.Any(), storage.TodoSearch{Query: "invoice", UserID: user.ID, Limit: 50}).Return(results, nil)
+
+		resultsJSON, err := json.Marshal(&results)
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos/search?q=invoice", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.JSONEq(t, string(resultsJSON), response.Body.String())
+	})
+
+	t.Run("search items without query", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos/search?q=+", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("add view with invalid sort", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		viewJSON, err := json.Marshal(model.View{Name: "urgent", Sort: "-priority"})
+		assert.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/views", bytes.NewBuffer(viewJSON))
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get view todos", func(t *testing.T) {
+		view := model.View{ID: "v1", Name: "done", Status: "done", Sort: "-name", UserID: user.ID}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetView(gomock.Any(), "v1").Return(view, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{
+			UserID: user.ID,
+			Status: "done",
+			Sort:   []storage.SortField{{Field: "name", Desc: true}},
+			Limit:  51,
+		}).Return([]model.TodoItem{
+			{ID: "2", Name: "b", Status: "done"},
+			{ID: "1", Name: "a", Status: "done"},
+		}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/views/v1/todos", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, response.Header().Get("Link"))
+
+		var todos []model.TodoItem
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&todos))
+		assert.Len(t, todos, 2)
+		assert.Equal(t, "2", todos[0].ID)
+	})
+
+	t.Run("get view todos page", func(t *testing.T) {
+		view := model.View{ID: "v1", Name: "done", Status: "done", Sort: "-name", UserID: user.ID}
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetView(gomock.Any(), "v1").Return(view, nil)
+		m.EXPECT().GetAllItems(gomock.Any(), storage.TodoFilter{
+			UserID: user.ID,
+			Status: "done",
+			Sort:   []storage.SortField{{Field: "name", Desc: true}},
+			Limit:  2,
+		}).Return([]model.TodoItem{
+			{ID: "2", Name: "b", Status: "done"},
+			{ID: "1", Name: "a", Status: "done"},
+		}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/views/v1/todos?limit=1", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Header().Get("Link"), "cursor=")
+
+		var todos []model.TodoItem
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&todos))
+		assert.Len(t, todos, 1)
+		assert.Equal(t, "2", todos[0].ID)
+	})
+
+	t.Run("get view of another user", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+		m.EXPECT().GetView(gomock.Any(), "v2").Return(model.View{ID: "v2", Name: "other", UserID: "someone"}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/views/v2", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	t.Run("get activity with invalid cursor", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/activity?cursor=%21%21", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get stats", func(t *testing.T) {
+		from := time.Date(2021, 10, 1, 0, 0, 0, 0, l)
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetStats(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter storage.StatsFilter) (model.Stats, error) {
+			assert.Equal(t, user.ID, filter.UserID)
+			assert.True(t, from.Equal(filter.From))
+			assert.True(t, from.AddDate(0, 0, 3).Equal(filter.To))
+			return model.Stats{
+				Buckets:  []model.StatsBucket{{Start: from.AddDate(0, 0, 1), Created: 2, Completed: 1}},
+				ByStatus: map[string]int{"new": 1},
+			}, nil
+		})
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/stats?from=2021-10-01&to=2021-10-03", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		stats := model.Stats{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&stats))
+		assert.Equal(t, model.BucketDay, stats.Bucket)
+		assert.Len(t, stats.Buckets, 3)
+		assert.Equal(t, 0, stats.Buckets[0].Created)
+		assert.Equal(t, 2, stats.Buckets[1].Created)
+		assert.Equal(t, 1, stats.Buckets[1].Completed)
+	})
+
+	t.Run("get agenda", func(t *testing.T) {
+		// daylight saving time ends in New York on 2021-11-07
+		timed := model.TodoItem{ID: "1", Name: "late", Date: time.Date(2021, 11, 7, 23, 30, 0, 0, l)}
+		allDay := model.TodoItem{ID: "2", Name: "holiday", Date: time.Date(2021, 11, 8, 0, 0, 0, 0, time.UTC), AllDay: true}
+		morning := model.TodoItem{ID: "3", Name: "early", Date: time.Date(2021, 11, 8, 9, 0, 0, 0, l)}
+
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetAllItems(gomock.Any(), gomock.Any()).Return([]model.TodoItem{morning, timed, allDay}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/agenda?from=2021-11-06&to=2021-11-08", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		agenda := model.Agenda{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&agenda))
+		assert.Equal(t, "America/New_York", agenda.Location)
+		assert.Len(t, agenda.Days, 3)
+		assert.Equal(t, "2021-11-06", agenda.Days[0].Date)
+		assert.Empty(t, agenda.Days[0].Todos)
+		assert.Len(t, agenda.Days[1].Todos, 1)
+		assert.Equal(t, "1", agenda.Days[1].Todos[0].ID)
+		assert.Len(t, agenda.Days[2].Todos, 2)
+		assert.Equal(t, "2", agenda.Days[2].Todos[0].ID)
+		assert.Equal(t, "3", agenda.Days[2].Todos[1].ID)
+	})
+
+	t.Run("get agenda in other time zone", func(t *testing.T) {
+		timed := model.TodoItem{ID: "1", Name: "late", Date: time.Date(2021, 11, 7, 23, 30, 0, 0, l)}
+
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetAllItems(gomock.Any(), gomock.Any()).Return([]model.TodoItem{timed}, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/agenda?from=2021-11-07&to=2021-11-08&tz=Asia/Tokyo", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		agenda := model.Agenda{}
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&agenda))
+		assert.Equal(t, "Asia/Tokyo", agenda.Location)
+		assert.Empty(t, agenda.Days[0].Todos)
+		assert.Len(t, agenda.Days[1].Todos, 1)
+	})
+
+	t.Run("get agenda with unknown time zone", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/agenda?tz=Mars/Olympus", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get today todos", func(t *testing.T) {
+		now := time.Now().In(l)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, l)
+		todos := []model.TodoItem{
+			{ID: "1", Name: "yesterday", Date: today.Add(-time.Hour)},
+			{ID: "2", Name: "noon", Date: today.Add(12 * time.Hour)},
+			{ID: "3", Name: "all day", Date: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), AllDay: true},
+			{ID: "4", Name: "tomorrow", Date: today.AddDate(0, 0, 1)},
+		}
+
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetAllItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, filter storage.TodoFilter) ([]model.TodoItem, error) {
+			assert.Equal(t, user.ID, filter.UserID)
+			assert.NotNil(t, filter.Query, "done todos are excluded")
+			assert.NotNil(t, filter.FromDate)
+			assert.NotNil(t, filter.ToDate)
+			return todos, nil
+		})
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos/today", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var got []model.TodoItem
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Len(t, got, 2)
+		assert.Equal(t, "3", got[0].ID)
+		assert.Equal(t, "2", got[1].ID)
+	})
+
+	t.Run("get overdue todos", func(t *testing.T) {
+		now := time.Now().In(l)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, l)
+		todos := []model.TodoItem{
+			{ID: "1", Name: "yesterday", Date: today.Add(-time.Hour)},
+			{ID: "2", Name: "noon", Date: today.Add(12 * time.Hour)},
+		}
+
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+		m.EXPECT().GetAllItems(gomock.Any(), gomock.Any()).Return(todos, nil)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos/overdue", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		var got []model.TodoItem
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+		assert.Len(t, got, 1)
+		assert.Equal(t, "1", got[0].ID)
+	})
+
+	t.Run("get upcoming todos with invalid days", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/todos/upcoming?days=-1", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("get stats with unknown bucket", func(t *testing.T) {
+		m.EXPECT().GetUser(gomock.Any(), user.ID).Return(user, nil).Times(2)
+
+		response := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/stats?bucket=month", nil)
+		assert.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token.TokenString)
+
+		server.Serve.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
 }
 
 /*
@@ -0,0 +1,73 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"todo/model"
+	"todo/storage"
+)
+
+// notifications handlers.
+func (t *Server) getNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	filter := storage.NotificationFilter{}
+	if val := r.URL.Query().Get("unread"); val != "" {
+		unread, err := strconv.ParseBool(val)
+		if err != nil {
+			t.handleError(fmt.Errorf("%q: %q: %w", "Error in getNotificationsHandler.", err, model.ErrBadRequest), w)
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getNotificationsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	filter.Limit = limit
+
+	page, err := t.service.GetNotifications(r.Context(), filter, r.URL.Query().Get("cursor"))
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getNotificationsHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getNotificationsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) getUnreadCountHandler(w http.ResponseWriter, r *http.Request) {
+	count, err := t.service.CountUnreadNotifications(r.Context())
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getUnreadCountHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(count); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getUnreadCountHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) readNotificationHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "notificationId")
+	err := t.service.ReadNotification(r.Context(), id)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in readNotificationHandler.", err), w)
+		return
+	}
+}
+
+func (t *Server) readAllNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	err := t.service.ReadAllNotifications(r.Context())
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in readAllNotificationsHandler.", err), w)
+		return
+	}
+}
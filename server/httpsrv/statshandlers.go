@@ -0,0 +1,24 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"todo/model"
+)
+
+// stats handlers.
+func (t *Server) getStatsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	stats, err := t.service.GetStats(r.Context(), q.Get("from"), q.Get("to"), q.Get("bucket"))
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getStatsHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getStatsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
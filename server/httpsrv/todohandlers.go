@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
 	"todo/model"
 	"todo/storage"
+	"todo/storage/query"
 )
 
 // todos handlers.
@@ -33,13 +36,44 @@ func (t *Server) getAllItemsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		filter.ToDate = &toDate
 	}
+	if q := r.URL.Query().Get("q"); q != "" {
+		expr, err := query.Parse(q)
+		if err != nil {
+			t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllItemsHandler.", err, model.ErrBadRequest), w)
+			return
+		}
+		filter.Query = expr
+	}
+	if spec := r.URL.Query().Get("sort"); spec != "" {
+		sort, err := storage.ParseSort(spec)
+		if err != nil {
+			t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllItemsHandler.", err, model.ErrBadRequest), w)
+			return
+		}
+		filter.Sort = sort
+	}
+
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	filter.Limit = limit
 
-	items, err := t.service.GetTodos(r.Context(), filter)
+	fields, err := storage.ParseFields(r.URL.Query().Get("fields"), storage.TodoFields)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	filter.Fields = fields
+
+	page, err := t.service.GetTodos(r.Context(), filter, r.URL.Query().Get("cursor"))
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in getAllItemsHandler.", err), w)
 		return
 	}
-	err = json.NewEncoder(w).Encode(items)
+	setNextLink(w, r, page.NextCursor)
+	err = encodeFields(w, page.Todos, fields)
 
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllItemsHandler.", err, model.ErrBadRequest), w)
@@ -47,6 +81,32 @@ func (t *Server) getAllItemsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// smartListHandler returns handler of smart todo list like today or overdue.
+func (t *Server) smartListHandler(list string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days := 0
+		if val := r.URL.Query().Get("days"); val != "" {
+			d, err := strconv.Atoi(val)
+			if err != nil {
+				t.handleError(fmt.Errorf("%q: %q: %w", "Error in smartListHandler.", err, model.ErrBadRequest), w)
+				return
+			}
+			days = d
+		}
+
+		todos, err := t.service.GetSmartList(r.Context(), list, days)
+		if err != nil {
+			t.handleError(fmt.Errorf("%q: %w", "Error in smartListHandler.", err), w)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(todos); err != nil {
+			t.handleError(fmt.Errorf("%q: %q: %w", "Error in smartListHandler.", err, model.ErrBadRequest), w)
+			return
+		}
+	}
+}
+
 func (t *Server) addItemHandler(w http.ResponseWriter, r *http.Request) {
 	todo := model.TodoItem{}
 	err := json.NewDecoder(r.Body).Decode(&todo)
@@ -69,9 +129,36 @@ func (t *Server) addItemHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// batchItemsHandler applies batch of todo changes, outcome of each todo is reported in response.
+func (t *Server) batchItemsHandler(w http.ResponseWriter, r *http.Request) {
+	batch := model.TodoBatch{}
+	err := json.NewDecoder(r.Body).Decode(&batch)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in batchItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	result, err := t.service.BatchTodos(r.Context(), batch)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in batchItemsHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in batchItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
 func (t *Server) getItemHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "todoId")
-	todo, err := t.service.GetTodo(r.Context(), id)
+	fields, err := storage.ParseFields(r.URL.Query().Get("fields"), storage.TodoFields)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getItemHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	todo, err := t.service.GetTodo(r.Context(), id, fields)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in getItemsHandler.", err), w)
 		return
@@ -80,7 +167,8 @@ func (t *Server) getItemHandler(w http.ResponseWriter, r *http.Request) {
 		t.handleError(fmt.Errorf("%q: %w", "Error in getItemHandler.", model.ErrNotFound), w)
 		return
 	}
-	err = json.NewEncoder(w).Encode(todo)
+	w.Header().Set("ETag", etag(todo.Version))
+	err = encodeFields(w, todo, fields)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getItemsHandler.", err, model.ErrBadRequest), w)
 		return
@@ -89,7 +177,12 @@ func (t *Server) getItemHandler(w http.ResponseWriter, r *http.Request) {
 
 func (t *Server) deleteItemHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "todoId")
-	err := t.service.DeleteTodo(r.Context(), id)
+	version, err := t.requiredVersion(r, id)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in deleteItemHandler.", err), w)
+		return
+	}
+	err = t.service.DeleteTodo(r.Context(), id, version)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in deleteItemHandler.", err), w)
 		return
@@ -104,9 +197,104 @@ func (t *Server) updateItemHandler(w http.ResponseWriter, r *http.Request) {
 		t.handleError(fmt.Errorf("%q: %q: %w", "updateItemHandler.", err, model.ErrBadRequest), w)
 		return
 	}
+	todo.Version, err = t.requiredVersion(r, id)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "updateItemHandler.", err), w)
+		return
+	}
 	err = t.service.UpdateTodo(r.Context(), id, todo)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in updateItemHandler.", err), w)
 		return
 	}
 }
+
+func (t *Server) searchItemsHandler(w http.ResponseWriter, r *http.Request) {
+	search := storage.TodoSearch{Query: r.URL.Query().Get("q")}
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in searchItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	search.Limit = limit
+
+	results, err := t.service.SearchTodos(r.Context(), search)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in searchItemsHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in searchItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+// etag returns entity tag of todo version.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// requiredVersion returns todo version required by If-Match header, 0 if any version matches.
+// If header lists several versions, current version of todo is required when it is listed.
+func (t *Server) requiredVersion(r *http.Request, id string) (int, error) {
+	versions, any, err := ifMatch(r)
+	if err != nil {
+		return 0, fmt.Errorf("%q: %w", err, model.ErrBadRequest)
+	}
+	switch {
+	case any:
+		return 0, nil
+	case len(versions) == 0:
+		return 0, fmt.Errorf("%q: %w", "If-Match lists no version of todo.", model.ErrVersionConflict)
+	case len(versions) == 1:
+		return versions[0], nil
+	}
+
+	todo, err := t.service.GetTodo(r.Context(), id, []string{"version"})
+	if err != nil {
+		return 0, err
+	}
+	for _, version := range versions {
+		if version == todo.Version {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("%q: %w", "If-Match does not list current version of todo.", model.ErrVersionConflict)
+}
+
+// ifMatch parses If-Match header into listed todo versions, any is true for missing header or "*".
+// If-Match compares tags strongly, so weak tags are left out, and so are tags that are not versions.
+func ifMatch(r *http.Request) (versions []int, any bool, err error) {
+	val := strings.TrimSpace(r.Header.Get("If-Match"))
+	if val == "" || val == "*" {
+		return nil, true, nil
+	}
+	for rest := val; rest != ""; {
+		rest = strings.TrimLeft(rest, " \t")
+		if strings.HasPrefix(rest, ",") {
+			rest = rest[1:]
+			continue
+		}
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[2:]
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, false, fmt.Errorf("invalid If-Match %q", val)
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return nil, false, fmt.Errorf("invalid If-Match %q", val)
+		}
+		tag := rest[1 : end+1]
+		rest = strings.TrimLeft(rest[end+2:], " \t")
+		if rest != "" && rest[0] != ',' {
+			return nil, false, fmt.Errorf("invalid If-Match %q", val)
+		}
+		if version, err := strconv.Atoi(tag); err == nil && version >= 1 && !weak {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false, nil
+}
@@ -17,14 +17,33 @@ func (t *Server) getAllUsersHandler(w http.ResponseWriter, r *http.Request) {
 	if val, ok := r.URL.Query()["username"]; ok {
 		filter.UserName = val[0]
 	}
+	filter.Query = r.URL.Query().Get("q")
 
-	users, err := t.service.GetUsers(r.Context(), filter)
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllUsersHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	filter.Limit = limit
+
+	fields, err := storage.ParseFields(r.URL.Query().Get("fields"), storage.UserFields)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllUsersHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+	if filter.Query != "" && len(fields) == 0 {
+		fields = storage.PublicUserFields
+	}
+	filter.Fields = fields
+
+	page, err := t.service.GetUsers(r.Context(), filter, r.URL.Query().Get("cursor"))
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w: ", "Error in getAllUsersHandler.", err), w)
 		return
 	}
+	setNextLink(w, r, page.NextCursor)
 
-	if err := json.NewEncoder(w).Encode(users); err != nil {
+	if err := encodeFields(w, page.Users, fields); err != nil {
 		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllUsersHandler.", err, model.ErrBadRequest), w)
 		return
 	}
@@ -59,13 +78,19 @@ func (t *Server) addUserHandler(w http.ResponseWriter, r *http.Request) {
 
 func (t *Server) getUserHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "userId")
-	user, err := t.service.GetUser(r.Context(), id)
+	fields, err := storage.ParseFields(r.URL.Query().Get("fields"), storage.UserFields)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in get user handler", err, model.ErrBadRequest), w)
+		return
+	}
+
+	user, err := t.service.GetUser(r.Context(), id, fields)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in get user handler", err), w)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(user)
+	err = encodeFields(w, user, fields)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %q: %w", "Error in get user handler", err, model.ErrBadRequest), w)
 		return
@@ -74,7 +99,11 @@ func (t *Server) getUserHandler(w http.ResponseWriter, r *http.Request) {
 
 func (t *Server) deleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "userId")
-	err := t.service.DeleteUser(r.Context(), id)
+	deletion := model.UserDeletion{
+		Todos:      r.URL.Query().Get("todos"),
+		ReassignTo: r.URL.Query().Get("reassignto"),
+	}
+	err := t.service.DeleteUser(r.Context(), id, deletion)
 	if err != nil {
 		t.handleError(fmt.Errorf("%q: %w", "Error in delete user handler", err), w)
 	}
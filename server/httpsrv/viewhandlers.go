@@ -0,0 +1,100 @@
+package httpsrv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"todo/model"
+)
+
+// views handlers.
+func (t *Server) getAllViewsHandler(w http.ResponseWriter, r *http.Request) {
+	views, err := t.service.GetViews(r.Context())
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getAllViewsHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(views); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getAllViewsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) addViewHandler(w http.ResponseWriter, r *http.Request) {
+	view := model.View{}
+	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in addViewHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	id, err := t.service.AddView(r.Context(), view)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in addViewHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(model.TodoID{ID: id}); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in addViewHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) getViewHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "viewId")
+	view, err := t.service.GetView(r.Context(), id)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getViewHandler.", err), w)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(view); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getViewHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
+
+func (t *Server) updateViewHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "viewId")
+	view := model.View{}
+	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in updateViewHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	if err := t.service.UpdateView(r.Context(), id, view); err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in updateViewHandler.", err), w)
+		return
+	}
+}
+
+func (t *Server) deleteViewHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "viewId")
+	if err := t.service.DeleteView(r.Context(), id); err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in deleteViewHandler.", err), w)
+		return
+	}
+}
+
+func (t *Server) getViewItemsHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "viewId")
+	limit, err := getLimit(r)
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getViewItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+
+	page, err := t.service.GetViewTodos(r.Context(), id, limit, r.URL.Query().Get("cursor"))
+	if err != nil {
+		t.handleError(fmt.Errorf("%q: %w", "Error in getViewItemsHandler.", err), w)
+		return
+	}
+
+	setNextLink(w, r, page.NextCursor)
+	if err := json.NewEncoder(w).Encode(page.Todos); err != nil {
+		t.handleError(fmt.Errorf("%q: %q: %w", "Error in getViewItemsHandler.", err, model.ErrBadRequest), w)
+		return
+	}
+}
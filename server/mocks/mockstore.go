@@ -5,7 +5,9 @@
 package mockstore
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 	model "todo/model"
 	storage "todo/storage"
 
@@ -35,148 +37,576 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AddActivity mocks base method.
+func (m *MockStorage) AddActivity(arg0 context.Context, arg1 model.Activity) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddActivity", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddActivity indicates an expected call of AddActivity.
+func (mr *MockStorageMockRecorder) AddActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActivity", reflect.TypeOf((*MockStorage)(nil).AddActivity), arg0, arg1)
+}
+
+// AddEvent mocks base method.
+func (m *MockStorage) AddEvent(arg0 context.Context, arg1 model.Event) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEvent indicates an expected call of AddEvent.
+func (mr *MockStorageMockRecorder) AddEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockStorage)(nil).AddEvent), arg0, arg1)
+}
+
 // AddItem mocks base method.
-func (m *MockStorage) AddItem(arg0 model.TodoItem) (string, error) {
+func (m *MockStorage) AddItem(arg0 context.Context, arg1 model.TodoItem) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddItem", arg0)
+	ret := m.ctrl.Call(m, "AddItem", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddItem indicates an expected call of AddItem.
-func (mr *MockStorageMockRecorder) AddItem(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) AddItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockStorage)(nil).AddItem), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockStorage)(nil).AddItem), arg0, arg1)
+}
+
+// AddItems mocks base method.
+func (m *MockStorage) AddItems(arg0 context.Context, arg1 []model.TodoItem) ([]storage.ItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItems", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddItems indicates an expected call of AddItems.
+func (mr *MockStorageMockRecorder) AddItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItems", reflect.TypeOf((*MockStorage)(nil).AddItems), arg0, arg1)
+}
+
+// AddNotification mocks base method.
+func (m *MockStorage) AddNotification(arg0 context.Context, arg1 model.Notification) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNotification", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddNotification indicates an expected call of AddNotification.
+func (mr *MockStorageMockRecorder) AddNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotification", reflect.TypeOf((*MockStorage)(nil).AddNotification), arg0, arg1)
 }
 
 // AddUser mocks base method.
-func (m *MockStorage) AddUser(arg0 model.User) (string, error) {
+func (m *MockStorage) AddUser(arg0 context.Context, arg1 model.User) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddUser", arg0)
+	ret := m.ctrl.Call(m, "AddUser", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddUser indicates an expected call of AddUser.
-func (mr *MockStorageMockRecorder) AddUser(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) AddUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockStorage)(nil).AddUser), arg0, arg1)
+}
+
+// AddView mocks base method.
+func (m *MockStorage) AddView(arg0 context.Context, arg1 model.View) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddView", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddView indicates an expected call of AddView.
+func (mr *MockStorageMockRecorder) AddView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddView", reflect.TypeOf((*MockStorage)(nil).AddView), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStorage) CountUnreadNotifications(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStorageMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStorage)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// DeleteEvent mocks base method.
+func (m *MockStorage) DeleteEvent(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEvent indicates an expected call of DeleteEvent.
+func (mr *MockStorageMockRecorder) DeleteEvent(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockStorage)(nil).AddUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEvent", reflect.TypeOf((*MockStorage)(nil).DeleteEvent), arg0, arg1)
 }
 
 // DeleteItem mocks base method.
-func (m *MockStorage) DeleteItem(arg0 string) error {
+func (m *MockStorage) DeleteItem(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteItem", arg0)
+	ret := m.ctrl.Call(m, "DeleteItem", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteItem indicates an expected call of DeleteItem.
-func (mr *MockStorageMockRecorder) DeleteItem(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockStorage)(nil).DeleteItem), arg0, arg1)
+}
+
+// DeleteItems mocks base method.
+func (m *MockStorage) DeleteItems(arg0 context.Context, arg1 []string) ([]storage.ItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItems", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteItems indicates an expected call of DeleteItems.
+func (mr *MockStorageMockRecorder) DeleteItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItems", reflect.TypeOf((*MockStorage)(nil).DeleteItems), arg0, arg1)
+}
+
+// DeleteNotificationsBefore mocks base method.
+func (m *MockStorage) DeleteNotificationsBefore(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationsBefore", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationsBefore indicates an expected call of DeleteNotificationsBefore.
+func (mr *MockStorageMockRecorder) DeleteNotificationsBefore(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItem", reflect.TypeOf((*MockStorage)(nil).DeleteItem), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationsBefore", reflect.TypeOf((*MockStorage)(nil).DeleteNotificationsBefore), arg0, arg1)
 }
 
 // DeleteUser mocks base method.
-func (m *MockStorage) DeleteUser(arg0 string) error {
+func (m *MockStorage) DeleteUser(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0)
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockStorageMockRecorder) DeleteUser(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteView mocks base method.
+func (m *MockStorage) DeleteView(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteView", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteView indicates an expected call of DeleteView.
+func (mr *MockStorageMockRecorder) DeleteView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteView", reflect.TypeOf((*MockStorage)(nil).DeleteView), arg0, arg1)
+}
+
+// GetAllActivities mocks base method.
+func (m *MockStorage) GetAllActivities(arg0 context.Context, arg1 storage.ActivityFilter) ([]model.Activity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllActivities", arg0, arg1)
+	ret0, _ := ret[0].([]model.Activity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllActivities indicates an expected call of GetAllActivities.
+func (mr *MockStorageMockRecorder) GetAllActivities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllActivities", reflect.TypeOf((*MockStorage)(nil).GetAllActivities), arg0, arg1)
 }
 
 // GetAllItems mocks base method.
-func (m *MockStorage) GetAllItems(arg0 storage.TodoFilter) ([]model.TodoItem, error) {
+func (m *MockStorage) GetAllItems(arg0 context.Context, arg1 storage.TodoFilter) ([]model.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllItems", arg0)
+	ret := m.ctrl.Call(m, "GetAllItems", arg0, arg1)
 	ret0, _ := ret[0].([]model.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllItems indicates an expected call of GetAllItems.
-func (mr *MockStorageMockRecorder) GetAllItems(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetAllItems(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllItems", reflect.TypeOf((*MockStorage)(nil).GetAllItems), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllItems", reflect.TypeOf((*MockStorage)(nil).GetAllItems), arg0, arg1)
+}
+
+// GetAllNotifications mocks base method.
+func (m *MockStorage) GetAllNotifications(arg0 context.Context, arg1 storage.NotificationFilter) ([]model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllNotifications indicates an expected call of GetAllNotifications.
+func (mr *MockStorageMockRecorder) GetAllNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotifications", reflect.TypeOf((*MockStorage)(nil).GetAllNotifications), arg0, arg1)
 }
 
 // GetAllUsers mocks base method.
-func (m *MockStorage) GetAllUsers(arg0 storage.UserFilter) ([]model.User, error) {
+func (m *MockStorage) GetAllUsers(arg0 context.Context, arg1 storage.UserFilter) ([]model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllUsers", arg0)
+	ret := m.ctrl.Call(m, "GetAllUsers", arg0, arg1)
 	ret0, _ := ret[0].([]model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllUsers indicates an expected call of GetAllUsers.
-func (mr *MockStorageMockRecorder) GetAllUsers(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetAllUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockStorage)(nil).GetAllUsers), arg0, arg1)
+}
+
+// GetAllViews mocks base method.
+func (m *MockStorage) GetAllViews(arg0 context.Context, arg1 string) ([]model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllViews", arg0, arg1)
+	ret0, _ := ret[0].([]model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllViews indicates an expected call of GetAllViews.
+func (mr *MockStorageMockRecorder) GetAllViews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllViews", reflect.TypeOf((*MockStorage)(nil).GetAllViews), arg0, arg1)
+}
+
+// GetBoard mocks base method.
+func (m *MockStorage) GetBoard(arg0 context.Context, arg1 string) (model.Board, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBoard", arg0, arg1)
+	ret0, _ := ret[0].(model.Board)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBoard indicates an expected call of GetBoard.
+func (mr *MockStorageMockRecorder) GetBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBoard", reflect.TypeOf((*MockStorage)(nil).GetBoard), arg0, arg1)
+}
+
+// GetEvents mocks base method.
+func (m *MockStorage) GetEvents(arg0 context.Context, arg1 int) ([]model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEvents", arg0, arg1)
+	ret0, _ := ret[0].([]model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEvents indicates an expected call of GetEvents.
+func (mr *MockStorageMockRecorder) GetEvents(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUsers", reflect.TypeOf((*MockStorage)(nil).GetAllUsers), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEvents", reflect.TypeOf((*MockStorage)(nil).GetEvents), arg0, arg1)
 }
 
 // GetItem mocks base method.
-func (m *MockStorage) GetItem(arg0 string) (model.TodoItem, error) {
+func (m *MockStorage) GetItem(arg0 context.Context, arg1 string) (model.TodoItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetItem", arg0)
+	ret := m.ctrl.Call(m, "GetItem", arg0, arg1)
 	ret0, _ := ret[0].(model.TodoItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetItem indicates an expected call of GetItem.
-func (mr *MockStorageMockRecorder) GetItem(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockStorage)(nil).GetItem), arg0, arg1)
+}
+
+// GetNotification mocks base method.
+func (m *MockStorage) GetNotification(arg0 context.Context, arg1 string) (model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotification", arg0, arg1)
+	ret0, _ := ret[0].(model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotification indicates an expected call of GetNotification.
+func (mr *MockStorageMockRecorder) GetNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotification", reflect.TypeOf((*MockStorage)(nil).GetNotification), arg0, arg1)
+}
+
+// GetStats mocks base method.
+func (m *MockStorage) GetStats(arg0 context.Context, arg1 storage.StatsFilter) (model.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", arg0, arg1)
+	ret0, _ := ret[0].(model.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockStorageMockRecorder) GetStats(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockStorage)(nil).GetItem), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockStorage)(nil).GetStats), arg0, arg1)
 }
 
 // GetUser mocks base method.
-func (m *MockStorage) GetUser(arg0 string) (model.User, error) {
+func (m *MockStorage) GetUser(arg0 context.Context, arg1 string) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0)
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
 	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUser indicates an expected call of GetUser.
-func (mr *MockStorageMockRecorder) GetUser(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), arg0, arg1)
+}
+
+// GetView mocks base method.
+func (m *MockStorage) GetView(arg0 context.Context, arg1 string) (model.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetView", arg0, arg1)
+	ret0, _ := ret[0].(model.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetView indicates an expected call of GetView.
+func (mr *MockStorageMockRecorder) GetView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetView", reflect.TypeOf((*MockStorage)(nil).GetView), arg0, arg1)
+}
+
+// LockBoard mocks base method.
+func (m *MockStorage) LockBoard(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBoard", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockBoard indicates an expected call of LockBoard.
+func (mr *MockStorageMockRecorder) LockBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBoard", reflect.TypeOf((*MockStorage)(nil).LockBoard), arg0, arg1)
+}
+
+// LockUsers mocks base method.
+func (m *MockStorage) LockUsers(arg0 context.Context, arg1 ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LockUsers", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUsers indicates an expected call of LockUsers.
+func (mr *MockStorageMockRecorder) LockUsers(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUsers", reflect.TypeOf((*MockStorage)(nil).LockUsers), varargs...)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStorage) MarkAllNotificationsRead(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStorageMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStorage)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStorage) MarkNotificationRead(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStorageMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStorage)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// ReassignItems mocks base method.
+func (m *MockStorage) ReassignItems(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReassignItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReassignItems indicates an expected call of ReassignItems.
+func (mr *MockStorageMockRecorder) ReassignItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReassignItems", reflect.TypeOf((*MockStorage)(nil).ReassignItems), arg0, arg1, arg2)
+}
+
+// SearchItems mocks base method.
+func (m *MockStorage) SearchItems(arg0 context.Context, arg1 storage.TodoSearch) ([]model.TodoSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchItems", arg0, arg1)
+	ret0, _ := ret[0].([]model.TodoSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockStorageMockRecorder) SearchItems(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStorage)(nil).GetUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockStorage)(nil).SearchItems), arg0, arg1)
+}
+
+// UpdateBoard mocks base method.
+func (m *MockStorage) UpdateBoard(arg0 context.Context, arg1 model.Board) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBoard", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBoard indicates an expected call of UpdateBoard.
+func (mr *MockStorageMockRecorder) UpdateBoard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBoard", reflect.TypeOf((*MockStorage)(nil).UpdateBoard), arg0, arg1)
 }
 
 // UpdateItem mocks base method.
-func (m *MockStorage) UpdateItem(arg0 model.TodoItem) error {
+func (m *MockStorage) UpdateItem(arg0 context.Context, arg1 model.TodoItem) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateItem", arg0)
+	ret := m.ctrl.Call(m, "UpdateItem", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateItem indicates an expected call of UpdateItem.
-func (mr *MockStorageMockRecorder) UpdateItem(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UpdateItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockStorage)(nil).UpdateItem), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockStorage)(nil).UpdateItem), arg0, arg1)
+}
+
+// UpdateItemPositions mocks base method.
+func (m *MockStorage) UpdateItemPositions(arg0 context.Context, arg1 []model.TodoItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPositions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemPositions indicates an expected call of UpdateItemPositions.
+func (mr *MockStorageMockRecorder) UpdateItemPositions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPositions", reflect.TypeOf((*MockStorage)(nil).UpdateItemPositions), arg0, arg1)
+}
+
+// UpdateItems mocks base method.
+func (m *MockStorage) UpdateItems(arg0 context.Context, arg1 []model.TodoItem) ([]storage.ItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItems", arg0, arg1)
+	ret0, _ := ret[0].([]storage.ItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItems indicates an expected call of UpdateItems.
+func (mr *MockStorageMockRecorder) UpdateItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItems", reflect.TypeOf((*MockStorage)(nil).UpdateItems), arg0, arg1)
 }
 
 // UpdateUser mocks base method.
-func (m *MockStorage) UpdateUser(arg0 model.User) error {
+func (m *MockStorage) UpdateUser(arg0 context.Context, arg1 model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", arg0)
+	ret := m.ctrl.Call(m, "UpdateUser", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockStorageMockRecorder) UpdateUser(arg0 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStorage)(nil).UpdateUser), arg0, arg1)
+}
+
+// UpdateView mocks base method.
+func (m *MockStorage) UpdateView(arg0 context.Context, arg1 model.View) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateView", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateView indicates an expected call of UpdateView.
+func (mr *MockStorageMockRecorder) UpdateView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateView", reflect.TypeOf((*MockStorage)(nil).UpdateView), arg0, arg1)
+}
+
+// WithTx mocks base method.
+func (m *MockStorage) WithTx(arg0 context.Context, arg1 func(storage.Storage) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockStorageMockRecorder) WithTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStorage)(nil).UpdateUser), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockStorage)(nil).WithTx), arg0, arg1)
 }
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"todo/model"
+	"todo/storage"
+)
+
+func (h *handlersService) GetActivity(ctx context.Context, filter storage.ActivityFilter, cursor string) (model.ActivityPage, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.ActivityPage{}, fmt.Errorf("%q: %q: %w", "Could not get activity.", err, model.ErrUnauthorized)
+	}
+	filter.UserID = userid
+
+	if cursor != "" {
+		date, id, err := decodeCursor(cursor)
+		if err != nil {
+			return model.ActivityPage{}, fmt.Errorf("%q: %q: %w", "Could not get activity.", err, model.ErrBadRequest)
+		}
+		filter.Before = &storage.Cursor{Date: date, ID: id}
+	}
+
+	limit := pageLimit(filter.Limit)
+	filter.Limit = limit + 1
+
+	activities, err := h.storage.GetAllActivities(ctx, filter)
+	if err != nil {
+		return model.ActivityPage{}, fmt.Errorf("%q: %q: %w", "Could not get activity.", err, model.ErrOperational)
+	}
+
+	page := model.ActivityPage{Activities: activities}
+	if len(activities) > limit {
+		page.Activities = activities[:limit]
+		last := page.Activities[limit-1]
+		page.NextCursor = encodeCursor(last.Date, last.ID)
+	}
+	return page, nil
+}
+
+// todoActivityType returns activity type for todo update.
+func todoActivityType(old, updated model.TodoItem) string {
+	if old.Status != model.StatusDone && updated.Status == model.StatusDone {
+		return model.ActivityTodoCompleted
+	}
+	return model.ActivityTodoUpdated
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+)
+
+const defaultAgendaDays = 7
+
+func (h *handlersService) GetAgenda(ctx context.Context, from, to, tz string) (model.Agenda, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.Agenda{}, fmt.Errorf("%q: %q: %w", "Could not get agenda.", err, model.ErrUnauthorized)
+	}
+
+	location, err := h.userLocation(ctx, userid)
+	if err != nil {
+		return model.Agenda{}, fmt.Errorf("%q: %q: %w", "Could not get agenda.", err, model.ErrOperational)
+	}
+	if tz != "" {
+		if location, err = time.LoadLocation(tz); err != nil {
+			return model.Agenda{}, fmt.Errorf("%q: %q: %w", "Could not get agenda.", err, model.ErrBadRequest)
+		}
+	}
+
+	start, end, err := agendaRange(from, to, time.Now(), location)
+	if err != nil {
+		return model.Agenda{}, fmt.Errorf("%q: %q: %w", "Could not get agenda.", err, model.ErrBadRequest)
+	}
+
+	// all-day todos are stored at midnight UTC of their date, so range
+	// in UTC is loaded as well and todos are put into days below
+	allDayStart := model.AllDayDate(start)
+	allDayEnd := model.AllDayDate(end)
+	fromDate, toDate := earliest(start, allDayStart), latest(end, allDayEnd)
+	todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: userid, FromDate: &fromDate, ToDate: &toDate})
+	if err != nil {
+		return model.Agenda{}, fmt.Errorf("%q: %q: %w", "Could not get agenda.", err, model.ErrOperational)
+	}
+
+	agenda := model.Agenda{
+		From:     start.Format(dateLayout),
+		To:       end.AddDate(0, 0, -1).Format(dateLayout),
+		Location: location.String(),
+	}
+	index := map[string]int{}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		index[day.Format(dateLayout)] = len(agenda.Days)
+		agenda.Days = append(agenda.Days, model.AgendaDay{Date: day.Format(dateLayout), Todos: []model.TodoItem{}})
+	}
+
+	for _, todo := range todos {
+		todo.Date = todo.DateIn(location)
+		k, ok := index[todo.Date.Format(dateLayout)]
+		if !ok {
+			continue
+		}
+		agenda.Days[k].Todos = append(agenda.Days[k].Todos, todo)
+	}
+	for _, day := range agenda.Days {
+		sortAgendaDay(day.Todos)
+	}
+	return agenda, nil
+}
+
+// agendaRange parses inclusive range of local dates into [start, end).
+// Missing from means today, missing to means a week from start.
+func agendaRange(from, to string, now time.Time, location *time.Location) (time.Time, time.Time, error) {
+	start := storage.BucketStart(now, model.BucketDay, location)
+	if from != "" {
+		t, err := time.ParseInLocation(dateLayout, from, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %q", from)
+		}
+		start = t
+	}
+
+	end := start.AddDate(0, 0, defaultAgendaDays)
+	if to != "" {
+		t, err := time.ParseInLocation(dateLayout, to, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %q", to)
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date is after to date")
+	}
+	if start.AddDate(0, 0, maxRangeDays).Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range is longer than %d days", maxRangeDays)
+	}
+	return start, end, nil
+}
+
+// sortAgendaDay orders all-day todos first, then by time.
+func sortAgendaDay(todos []model.TodoItem) {
+	sort.SliceStable(todos, func(a, b int) bool {
+		if todos[a].AllDay != todos[b].AllDay {
+			return todos[a].AllDay
+		}
+		return storage.ItemBefore(todos[a], todos[b], []storage.SortField{{Field: "date"}})
+	})
+}
+
+// allDayDate returns date of all-day todo, today in users location when date is empty.
+func (h *handlersService) allDayDate(ctx context.Context, userid string, date time.Time) (time.Time, error) {
+	if date.IsZero() {
+		location, err := h.userLocation(ctx, userid)
+		if err != nil {
+			return time.Time{}, err
+		}
+		date = time.Now().In(location)
+	}
+	return model.AllDayDate(date), nil
+}
+
+func earliest(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
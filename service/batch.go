@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"todo/model"
+	"todo/storage"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// maxBatchSize limits number of todos in single batch.
+const maxBatchSize = 1000
+
+// Errors reported in results of todos batch couldn't change.
+const (
+	batchNotFound        = "not found"
+	batchDuplicate       = "duplicate id"
+	batchVersionConflict = "version conflict"
+)
+
+// BatchTodos applies todo changes of batch in one transaction.
+// Missing todos, malformed ids, todos of other users, stale versions and todos
+// repeated in batch are reported in results and do not stop other changes.
+func (h *handlersService) BatchTodos(ctx context.Context, batch model.TodoBatch) (model.TodoBatchResult, error) {
+	if len(batch.Add)+len(batch.Update)+len(batch.Delete) > maxBatchSize {
+		return model.TodoBatchResult{}, fmt.Errorf("%q: %w", fmt.Sprintf("Could not apply batch, it has more than %d todos.", maxBatchSize), model.ErrBadRequest)
+	}
+
+	result := model.TodoBatchResult{
+		Add:    make([]model.TodoResult, len(batch.Add)),
+		Update: make([]model.TodoResult, len(batch.Update)),
+		Delete: make([]model.TodoResult, len(batch.Delete)),
+	}
+	err := h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrUnauthorized)
+		}
+
+		existing, err := h.batchTodos(ctx, userid, batch)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+		}
+		seen := map[string]bool{}
+		check := func(todo model.TodoItem) (model.TodoItem, string) {
+			old, ok := existing[todo.ID]
+			if !ok {
+				return model.TodoItem{}, batchNotFound
+			}
+			if seen[todo.ID] {
+				return model.TodoItem{}, batchDuplicate
+			}
+			seen[todo.ID] = true
+			if todo.Version != 0 && todo.Version != old.Version {
+				return model.TodoItem{}, batchVersionConflict
+			}
+			return old, ""
+		}
+
+		adds := make([]model.TodoItem, 0, len(batch.Add))
+		for _, todo := range batch.Add {
+			todo.UserID = userid
+			if todo.AllDay {
+				if todo.Date, err = h.allDayDate(ctx, userid, todo.Date); err != nil {
+					return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+				}
+			}
+			adds = append(adds, todo)
+		}
+
+		var updates, previous []model.TodoItem
+		var updated []int // index in batch of each update
+		for n, todo := range batch.Update {
+			old, failed := check(todo)
+			if failed != "" {
+				result.Update[n] = model.TodoResult{ID: todo.ID, Error: failed}
+				continue
+			}
+			todo.UserID = userid
+			todo.Position = old.Position
+			if todo.AllDay {
+				if todo.Date, err = h.allDayDate(ctx, userid, todo.Date); err != nil {
+					return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+				}
+			}
+			updates = append(updates, todo)
+			previous = append(previous, old)
+			updated = append(updated, n)
+		}
+
+		var deletes []model.TodoItem
+		var deleted []int
+		for n, todo := range batch.Delete {
+			old, failed := check(todo)
+			if failed != "" {
+				result.Delete[n] = model.TodoResult{ID: todo.ID, Error: failed}
+				continue
+			}
+			deletes = append(deletes, old)
+			deleted = append(deleted, n)
+		}
+
+		if len(adds) > 0 {
+			results, err := h.storage.AddItems(ctx, adds)
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrBadRequest)
+			}
+			for n, r := range results {
+				result.Add[n] = model.TodoResult{ID: r.ID, Version: r.Version}
+				todo := adds[n]
+				todo.ID = r.ID
+				if err := h.publish(ctx, todoEvent(model.ActivityTodoCreated, model.TodoItem{}, todo)); err != nil {
+					return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+				}
+			}
+		}
+
+		if len(updates) > 0 {
+			results, err := h.storage.UpdateItems(ctx, updates)
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrBadRequest)
+			}
+			for k, r := range results {
+				if r.Missing {
+					result.Update[updated[k]] = model.TodoResult{ID: r.ID, Error: batchNotFound}
+					continue
+				}
+				result.Update[updated[k]] = model.TodoResult{ID: r.ID, Version: r.Version}
+				if err := h.publish(ctx, todoEvent(todoActivityType(previous[k], updates[k]), previous[k], updates[k])); err != nil {
+					return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+				}
+			}
+		}
+
+		if len(deletes) > 0 {
+			ids := make([]string, 0, len(deletes))
+			for _, todo := range deletes {
+				ids = append(ids, todo.ID)
+			}
+			results, err := h.storage.DeleteItems(ctx, ids)
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+			}
+			for k, r := range results {
+				if r.Missing {
+					result.Delete[deleted[k]] = model.TodoResult{ID: r.ID, Error: batchNotFound}
+					continue
+				}
+				result.Delete[deleted[k]] = model.TodoResult{ID: r.ID}
+				if err := h.publish(ctx, todoEvent(model.ActivityTodoDeleted, deletes[k], deletes[k])); err != nil {
+					return fmt.Errorf("%q: %q: %w", "Could not apply batch.", err, model.ErrOperational)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.TodoBatchResult{}, err
+	}
+	return result, nil
+}
+
+// batchTodos returns todos of user updated or deleted by batch, read with single query.
+// Malformed ids are left out of the query and so reported as not found.
+func (h *handlersService) batchTodos(ctx context.Context, userid string, batch model.TodoBatch) (map[string]model.TodoItem, error) {
+	ids := make([]string, 0, len(batch.Update)+len(batch.Delete))
+	for _, todos := range [][]model.TodoItem{batch.Update, batch.Delete} {
+		for _, todo := range todos {
+			if _, err := uuid.FromString(todo.ID); err == nil {
+				ids = append(ids, todo.ID)
+			}
+		}
+	}
+	existing := map[string]model.TodoItem{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: userid, IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range todos {
+		existing[todo.ID] = todo
+	}
+	return existing, nil
+}
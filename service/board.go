@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"todo/model"
+	"todo/storage"
+)
+
+// defaultBoardColumns are used when user has not configured board.
+var defaultBoardColumns = []model.BoardColumn{{Status: model.StatusNew}, {Status: model.StatusDone}}
+
+func (h *handlersService) GetBoard(ctx context.Context) (model.Board, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("%q: %q: %w", "Could not get board.", err, model.ErrUnauthorized)
+	}
+
+	board, err := h.storage.GetBoard(ctx, userid)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("%q: %q: %w", "Could not get board.", err, model.ErrOperational)
+	}
+
+	todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: userid})
+	if err != nil {
+		return model.Board{}, fmt.Errorf("%q: %q: %w", "Could not get board.", err, model.ErrOperational)
+	}
+
+	return buildBoard(board, todos), nil
+}
+
+func (h *handlersService) UpdateBoard(ctx context.Context, board model.Board) error {
+	seen := map[string]bool{}
+	for _, column := range board.Columns {
+		if column.Status == "" {
+			return fmt.Errorf("%q: %w", "Could not update board: column status is empty.", model.ErrBadRequest)
+		}
+		if seen[column.Status] {
+			return fmt.Errorf("%q: %w", fmt.Sprintf("Could not update board: column %q is duplicated.", column.Status), model.ErrBadRequest)
+		}
+		if column.WIPLimit < 0 {
+			return fmt.Errorf("%q: %w", fmt.Sprintf("Could not update board: WIP limit of column %q is negative.", column.Status), model.ErrBadRequest)
+		}
+		seen[column.Status] = true
+	}
+
+	return h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update board.", err, model.ErrUnauthorized)
+		}
+		if err := h.storage.LockBoard(ctx, userid); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update board.", err, model.ErrOperational)
+		}
+
+		board.UserID = userid
+		if err := h.storage.UpdateBoard(ctx, board); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update board.", err, model.ErrOperational)
+		}
+		return nil
+	})
+}
+
+// MoveCard moves todo to column and position on board.
+// Moves of one user are serialised, so concurrent moves cannot exceed WIP limit together.
+// Only moved todo is updated, other cards of column get new positions without new versions.
+func (h *handlersService) MoveCard(ctx context.Context, id string, move model.CardMove) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrUnauthorized)
+		}
+
+		todo, err := h.storage.GetItem(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		if todo.ID == "" || todo.UserID != userid {
+			return fmt.Errorf("%q: %w", "Could not move todo.", model.ErrNotFound)
+		}
+
+		if move.Status == "" {
+			return fmt.Errorf("%q: %w", "Could not move todo: target column is not provided.", model.ErrBadRequest)
+		}
+		if move.Position < 0 {
+			return fmt.Errorf("%q: %w", "Could not move todo: position is negative.", model.ErrBadRequest)
+		}
+
+		if err := h.storage.LockBoard(ctx, userid); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		board, err := h.storage.GetBoard(ctx, userid)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		column, ok := findColumn(board, move.Status)
+		if !ok && len(board.Columns) > 0 {
+			return fmt.Errorf("%q: %w", fmt.Sprintf("Could not move todo: board has no column %q.", move.Status), model.ErrBadRequest)
+		}
+
+		todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: userid, Status: move.Status})
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		sortCards(todos)
+
+		cards := make([]model.TodoItem, 0, len(todos)+1)
+		for _, card := range todos {
+			if card.ID != todo.ID {
+				cards = append(cards, card)
+			}
+		}
+
+		if column.WIPLimit > 0 && todo.Status != move.Status && len(cards) >= column.WIPLimit {
+			return fmt.Errorf("%q: %w", fmt.Sprintf("Could not move todo: column %q has reached its WIP limit of %d.", move.Status, column.WIPLimit), model.ErrBadRequest)
+		}
+
+		previous := todo
+		todo.Status = move.Status
+		position := move.Position
+		if position > len(cards) {
+			position = len(cards)
+		}
+		cards = append(cards[:position], append([]model.TodoItem{todo}, cards[position:]...)...)
+
+		var siblings []model.TodoItem
+		for idx, card := range cards {
+			if card.ID == todo.ID {
+				todo.Position = idx
+			} else if card.Position != idx {
+				card.Position = idx
+				siblings = append(siblings, card)
+			}
+		}
+		if err := h.storage.UpdateItem(ctx, todo); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		if len(siblings) > 0 {
+			if err := h.storage.UpdateItemPositions(ctx, siblings); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+			}
+		}
+
+		if err := h.publish(ctx, todoEvent(todoActivityType(previous, todo), previous, todo)); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not move todo.", err, model.ErrOperational)
+		}
+		return nil
+	})
+}
+
+// buildBoard places todos into columns of configured board.
+// Todos with statuses missing on board get extra columns at the end.
+func buildBoard(board model.Board, todos []model.TodoItem) model.Board {
+	columns := board.Columns
+	if len(columns) == 0 {
+		columns = defaultBoardColumns
+	}
+
+	result := model.Board{UserID: board.UserID, Columns: make([]model.BoardColumn, 0, len(columns))}
+	index := map[string]int{}
+	for _, column := range columns {
+		index[column.Status] = len(result.Columns)
+		result.Columns = append(result.Columns, model.BoardColumn{Status: column.Status, WIPLimit: column.WIPLimit})
+	}
+
+	var extra []string
+	for _, todo := range todos {
+		if _, ok := index[todo.Status]; !ok {
+			index[todo.Status] = -1
+			extra = append(extra, todo.Status)
+		}
+	}
+	sort.Strings(extra)
+	for _, status := range extra {
+		index[status] = len(result.Columns)
+		result.Columns = append(result.Columns, model.BoardColumn{Status: status})
+	}
+
+	for _, todo := range todos {
+		idx := index[todo.Status]
+		result.Columns[idx].Cards = append(result.Columns[idx].Cards, todo)
+	}
+	for idx := range result.Columns {
+		sortCards(result.Columns[idx].Cards)
+	}
+	return result
+}
+
+func findColumn(board model.Board, status string) (model.BoardColumn, bool) {
+	for _, column := range board.Columns {
+		if column.Status == status {
+			return column, true
+		}
+	}
+	return model.BoardColumn{}, false
+}
+
+// sortCards orders todos by board position, older todos first on ties.
+func sortCards(cards []model.TodoItem) {
+	sort.SliceStable(cards, func(a, b int) bool {
+		if cards[a].Position != cards[b].Position {
+			return cards[a].Position < cards[b].Position
+		}
+		if !cards[a].Date.Equal(cards[b].Date) {
+			return cards[a].Date.Before(cards[b].Date)
+		}
+		return cards[a].ID < cards[b].ID
+	})
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo/model"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// encodeCursor returns opaque cursor string for given position.
+func encodeCursor(date time.Time, id string) string {
+	raw := strconv.FormatInt(date.UnixNano(), 10) + "," + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeCursor parses cursor string produced by encodeCursor.
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor: %v", err)
+	}
+	parts := strings.SplitN(string(raw), ",", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	nsec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor: %v", err)
+	}
+	return time.Unix(0, nsec).UTC(), parts[1], nil
+}
+
+// encodeIDCursor returns opaque cursor string for listings ordered by id only.
+func encodeIDCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// decodeIDCursor parses cursor string produced by encodeIDCursor.
+func decodeIDCursor(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor: %v", err)
+	}
+	if len(raw) == 0 {
+		return "", fmt.Errorf("invalid cursor")
+	}
+	return string(raw), nil
+}
+
+// encodeTodoCursor returns opaque cursor string for todo listing.
+// Cursor keeps values of all sortable fields, so it works with any sort order.
+func encodeTodoCursor(todo model.TodoItem) string {
+	raw, _ := json.Marshal(model.TodoItem{
+		ID:       todo.ID,
+		Name:     todo.Name,
+		Date:     todo.Date.UTC(),
+		Status:   todo.Status,
+		Position: todo.Position,
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeTodoCursor parses cursor string produced by encodeTodoCursor.
+func decodeTodoCursor(cursor string) (model.TodoItem, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return model.TodoItem{}, fmt.Errorf("invalid cursor: %v", err)
+	}
+	todo := model.TodoItem{}
+	if err := json.Unmarshal(raw, &todo); err != nil {
+		return model.TodoItem{}, fmt.Errorf("invalid cursor: %v", err)
+	}
+	if todo.ID == "" {
+		return model.TodoItem{}, fmt.Errorf("invalid cursor")
+	}
+	return todo, nil
+}
+
+// pageLimit normalizes requested page size.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
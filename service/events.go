@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"todo/model"
+	"todo/storage"
+)
+
+// event describes change made through service.
+type event struct {
+	activity model.Activity
+	todo     model.TodoItem
+	previous model.TodoItem // empty for created todos
+}
+
+// eventHandler reacts to changes made through service.
+// Handlers get service they are published from, so they write through the same transaction.
+type eventHandler func(h *handlersService, ctx context.Context, e event) error
+
+var mentionRe = regexp.MustCompile(`@([\w.-]+)`)
+
+func todoEvent(activityType string, previous, todo model.TodoItem) event {
+	return event{
+		activity: model.Activity{
+			Type:     activityType,
+			UserID:   todo.UserID,
+			TodoID:   todo.ID,
+			TodoName: todo.Name,
+		},
+		todo:     todo,
+		previous: previous,
+	}
+}
+
+func (h *handlersService) publish(ctx context.Context, e event) error {
+	for _, handle := range h.eventHandlers {
+		if err := handle(h, ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *handlersService) recordActivity(ctx context.Context, e event) error {
+	_, err := h.storage.AddActivity(ctx, e.activity)
+	return err
+}
+
+// recordOutbox writes domain event for todo change to outbox,
+// so it is delivered only if transaction making the change commits.
+// Payload is todo as stored after change, or as it was before deletion.
+func (h *handlersService) recordOutbox(ctx context.Context, e event) error {
+	eventType := model.EventTodoUpdated
+	todo := e.todo
+	switch e.activity.Type {
+	case model.ActivityTodoCreated:
+		eventType = model.EventTodoCreated
+	case model.ActivityTodoDeleted:
+		eventType = model.EventTodoDeleted
+	}
+	if eventType != model.EventTodoDeleted {
+		stored, err := h.storage.GetItem(ctx, todo.ID)
+		if err != nil {
+			return err
+		}
+		todo = stored
+	}
+	return h.addEvent(ctx, eventType, todo.UserID, todo)
+}
+
+// addEvent writes domain event with payload encoded as JSON to outbox.
+func (h *handlersService) addEvent(ctx context.Context, eventType, userID string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = h.storage.AddEvent(ctx, model.Event{Type: eventType, UserID: userID, Payload: b})
+	return err
+}
+
+// notifyMentions notifies users mentioned as @username in todo name.
+// Users already mentioned before update are not notified again.
+// Usernames are matched ignoring case, so each user is notified once.
+func (h *handlersService) notifyMentions(ctx context.Context, e event) error {
+	if e.activity.Type == model.ActivityTodoDeleted {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, username := range mentions(e.previous.Name) {
+		seen[strings.ToLower(username)] = true
+	}
+
+	var actor model.User
+	for _, username := range mentions(e.todo.Name) {
+		if seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+
+		users, err := h.storage.GetAllUsers(ctx, storage.UserFilter{UserName: username})
+		if err != nil {
+			return err
+		}
+		for _, u := range users {
+			if u.ID == e.todo.UserID {
+				continue
+			}
+			if actor.ID == "" {
+				if actor, err = h.storage.GetUser(ctx, e.todo.UserID); err != nil {
+					return err
+				}
+			}
+			_, err := h.storage.AddNotification(ctx, model.Notification{
+				Type:    model.NotificationMention,
+				UserID:  u.ID,
+				ActorID: actor.ID,
+				TodoID:  e.todo.ID,
+				Message: fmt.Sprintf("%s mentioned you in %q", actor.UserName, e.todo.Name),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// notifyAssignment notifies heir that count todos of deleted user were reassigned to it.
+func (h *handlersService) notifyAssignment(ctx context.Context, actorID string, deleted, heir model.User, count int) error {
+	if count == 0 {
+		return nil
+	}
+	_, err := h.storage.AddNotification(ctx, model.Notification{
+		Type:    model.NotificationAssignment,
+		UserID:  heir.ID,
+		ActorID: actorID,
+		Message: fmt.Sprintf("%d todos of %s were reassigned to you", count, deleted.UserName),
+	})
+	return err
+}
+
+// mentions returns usernames mentioned in text.
+func mentions(text string) []string {
+	var usernames []string
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		usernames = append(usernames, m[1])
+	}
+	return usernames
+}
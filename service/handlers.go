@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/golang-jwt/jwt"
@@ -13,57 +14,132 @@ import (
 
 // Handlers represents handlers interface.
 type Handlers interface {
-	GetTodos(ctx context.Context, filter storage.TodoFilter) ([]model.TodoItem, error)
+	GetTodos(ctx context.Context, filter storage.TodoFilter, cursor string) (model.TodoPage, error)
 	AddTodo(ctx context.Context, todo model.TodoItem) (string, error)
-	GetTodo(ctx context.Context, id string) (model.TodoItem, error)
-	DeleteTodo(ctx context.Context, id string) error
-	UpdateTodo(ctx context.Context, id string, user model.TodoItem) error
+	GetTodo(ctx context.Context, id string, fields []string) (model.TodoItem, error)
+	DeleteTodo(ctx context.Context, id string, version int) error
+	UpdateTodo(ctx context.Context, id string, todo model.TodoItem) error
+	BatchTodos(ctx context.Context, batch model.TodoBatch) (model.TodoBatchResult, error)
+	SearchTodos(ctx context.Context, search storage.TodoSearch) ([]model.TodoSearchResult, error)
+	GetSmartList(ctx context.Context, list string, days int) ([]model.TodoItem, error)
 
 	AddUser(ctx context.Context, user model.User) (string, error)
-	DeleteUser(ctx context.Context, id string) error
+	DeleteUser(ctx context.Context, id string, deletion model.UserDeletion) error
 	UpdateUser(ctx context.Context, id string, user model.User) error
-	GetUser(ctx context.Context, id string) (model.User, error)
-	GetUsers(ctx context.Context, filter storage.UserFilter) ([]model.User, error)
+	GetUser(ctx context.Context, id string, fields []string) (model.User, error)
+	GetUsers(ctx context.Context, filter storage.UserFilter, cursor string) (model.UserPage, error)
 	LoginUser(ctx context.Context, credentials model.Credentials) (model.Token, error)
 
+	GetActivity(ctx context.Context, filter storage.ActivityFilter, cursor string) (model.ActivityPage, error)
+	GetStats(ctx context.Context, from, to, bucket string) (model.Stats, error)
+	GetAgenda(ctx context.Context, from, to, tz string) (model.Agenda, error)
+
+	GetNotifications(ctx context.Context, filter storage.NotificationFilter, cursor string) (model.NotificationPage, error)
+	CountUnreadNotifications(ctx context.Context) (model.UnreadCount, error)
+	ReadNotification(ctx context.Context, id string) error
+	ReadAllNotifications(ctx context.Context) error
+	PurgeNotifications(ctx context.Context) error
+	RemindDueTodos(ctx context.Context) error
+
+	GetBoard(ctx context.Context) (model.Board, error)
+	UpdateBoard(ctx context.Context, board model.Board) error
+	MoveCard(ctx context.Context, id string, move model.CardMove) error
+
+	AddView(ctx context.Context, view model.View) (string, error)
+	GetViews(ctx context.Context) ([]model.View, error)
+	GetView(ctx context.Context, id string) (model.View, error)
+	UpdateView(ctx context.Context, id string, view model.View) error
+	DeleteView(ctx context.Context, id string) error
+	GetViewTodos(ctx context.Context, id string, limit int, cursor string) (model.TodoPage, error)
+
 	ValidateToken(ctx context.Context, tokenString string) (*model.Claims, error)
-	AuthenticateUser(credentials model.Credentials) (string, error)
+	AuthenticateUser(ctx context.Context, credentials model.Credentials) (string, error)
 	GenerateToken(id string, secretKey string) (token model.Token, err error)
 	HashPassword(password string) (string, error)
 	CheckPasswordHash(password, hash string) bool
 }
 
 type handlersService struct {
-	storage storage.Storage
-	config  *config.Config
+	storage       storage.Storage
+	config        *config.Config
+	eventHandlers []eventHandler
 }
 
 // NewService returns handlers service struct.
 func NewService(storage storage.Storage, c *config.Config) Handlers {
-	return &handlersService{storage: storage, config: c}
+	h := &handlersService{storage: storage, config: c}
+	h.eventHandlers = []eventHandler{(*handlersService).recordActivity, (*handlersService).notifyMentions, (*handlersService).recordOutbox}
+	return h
 }
 
-func (h *handlersService) GetUsers(ctx context.Context, filter storage.UserFilter) ([]model.User, error) {
+func (h *handlersService) GetUsers(ctx context.Context, filter storage.UserFilter, cursor string) (model.UserPage, error) {
 	_, err := h.getUserFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("%q %q %w", "Could not get all users.", err, model.ErrUnauthorized)
+		return model.UserPage{}, fmt.Errorf("%q %q %w", "Could not get all users.", err, model.ErrUnauthorized)
 	}
 
-	users, err := h.storage.GetAllUsers(filter)
+	if filter.Query != "" {
+		// directory search shows public profiles only
+		for _, f := range filter.Fields {
+			if !isPublicUserField(f) {
+				return model.UserPage{}, fmt.Errorf("%q: %q: %w", "Could not get all users.", fmt.Sprintf("field %q is not public", f), model.ErrBadRequest)
+			}
+		}
+		if len(filter.Fields) == 0 {
+			filter.Fields = storage.PublicUserFields
+		}
+	}
+
+	if cursor != "" {
+		id, err := decodeIDCursor(cursor)
+		if err != nil {
+			return model.UserPage{}, fmt.Errorf("%q: %q: %w", "Could not get all users.", err, model.ErrBadRequest)
+		}
+		filter.AfterID = id
+	}
+
+	limit := pageLimit(filter.Limit)
+	filter.Limit = limit + 1
+
+	users, err := h.storage.GetAllUsers(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %q: %w", "Could not get all users.", err, model.ErrOperational)
+		return model.UserPage{}, fmt.Errorf("%q: %q: %w", "Could not get all users.", err, model.ErrOperational)
 	}
 
-	return users, nil
+	page := model.UserPage{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = encodeIDCursor(page.Users[limit-1].ID)
+	}
+	return page, nil
 }
 
-func (h *handlersService) GetUser(ctx context.Context, id string) (model.User, error) {
+func isPublicUserField(field string) bool {
+	for _, f := range storage.PublicUserFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUser returns user with given fields loaded, empty fields load whole user.
+func (h *handlersService) GetUser(ctx context.Context, id string, fields []string) (model.User, error) {
 	_, err := h.getUserFromContext(ctx)
 	if err != nil {
 		return model.User{}, fmt.Errorf("%q: %q: %w", "Could not get user.", err, model.ErrUnauthorized)
 	}
 
-	user, err := h.storage.GetUser(id)
+	var user model.User
+	if len(fields) == 0 {
+		user, err = h.storage.GetUser(ctx, id)
+	} else {
+		var users []model.User
+		users, err = h.storage.GetAllUsers(ctx, storage.UserFilter{IDs: []string{id}, Fields: fields})
+		if len(users) > 0 {
+			user = users[0]
+		}
+	}
 	if err != nil {
 		return model.User{}, fmt.Errorf("%q: %q: %w", "Could not get user.", err, model.ErrOperational)
 	}
@@ -76,56 +152,129 @@ func (h *handlersService) GetUser(ctx context.Context, id string) (model.User, e
 func (h *handlersService) AddUser(ctx context.Context, user model.User) (string, error) {
 	user.Password, _ = h.HashPassword(user.Password)
 
-	id, err := h.storage.AddUser(user)
+	var id string
+	err := h.inTx(ctx, func(h *handlersService) error {
+		var err error
+		id, err = h.storage.AddUser(ctx, user)
+		if errors.Is(err, model.ErrConflict) {
+			return fmt.Errorf("%q: %q: %w", "Could not add user, username is taken.", err, model.ErrConflict)
+		}
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not add user", err, model.ErrBadRequest)
+		}
+
+		user.ID = id
+		if err := h.addEvent(ctx, model.EventUserCreated, id, user); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not add user.", err, model.ErrOperational)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("%q: %q: %w", "Could not add user", err, model.ErrBadRequest)
+		return "", err
 	}
 	return id, nil
 }
 
-func (h *handlersService) DeleteUser(ctx context.Context, id string) error {
-	_, err := h.getUserFromContext(ctx)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrUnauthorized)
-	}
-
-	user, err := h.storage.GetUser(id)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
-	}
-	if user.ID == "" {
-		return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrNotFound)
-	}
-
-	if err := h.storage.DeleteUser(id); err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not get user.", err, model.ErrOperational)
-	}
-	return nil
+// DeleteUser deletes user, its todos are handled according to deletion policy.
+func (h *handlersService) DeleteUser(ctx context.Context, id string, deletion model.UserDeletion) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		actorID, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrUnauthorized)
+		}
+
+		ids := []string{id}
+		if deletion.Todos == model.DeleteTodosReassign {
+			ids = append(ids, deletion.ReassignTo)
+		}
+		if err := h.storage.LockUsers(ctx, ids...); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+		}
+
+		user, err := h.storage.GetUser(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+		}
+		if user.ID == "" {
+			return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrNotFound)
+		}
+
+		switch deletion.Todos {
+		case "", model.DeleteTodosRefuse:
+			todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: id, Limit: 1, Fields: []string{"id"}})
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+			}
+			if len(todos) > 0 {
+				return fmt.Errorf("%q: %w", "Could not delete user.", model.ErrUserHasTodos)
+			}
+		case model.DeleteTodosCascade:
+		case model.DeleteTodosReassign:
+			if deletion.ReassignTo == "" || deletion.ReassignTo == id {
+				return fmt.Errorf("%q: %w", "Could not delete user, invalid user to reassign todos to.", model.ErrBadRequest)
+			}
+			target, err := h.storage.GetUser(ctx, deletion.ReassignTo)
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+			}
+			if target.ID == "" {
+				return fmt.Errorf("%q: %w", "Could not delete user, user to reassign todos to does not exist.", model.ErrBadRequest)
+			}
+			todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: id, Fields: []string{"id"}})
+			if err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+			}
+			if err := h.storage.ReassignItems(ctx, id, target.ID); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+			}
+			if err := h.notifyAssignment(ctx, actorID, user, target, len(todos)); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+			}
+		default:
+			return fmt.Errorf("%q: %q: %w", "Could not delete user, unknown todos policy.", deletion.Todos, model.ErrBadRequest)
+		}
+
+		if err := h.storage.DeleteUser(ctx, id); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not get user.", err, model.ErrOperational)
+		}
+		if err := h.addEvent(ctx, model.EventUserDeleted, id, user); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete user.", err, model.ErrOperational)
+		}
+		return nil
+	})
 }
 
 func (h *handlersService) UpdateUser(ctx context.Context, id string, user model.User) error {
-	_, err := h.getUserFromContext(ctx)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrUnauthorized)
-	}
-
-	u, err := h.storage.GetUser(id)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrOperational)
-	}
-	if u.ID == "" {
-		return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrNotFound)
-	}
-	user.ID = id
-	err = h.storage.UpdateUser(user)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not update user", err, model.ErrBadRequest)
-	}
-	return nil
+	return h.inTx(ctx, func(h *handlersService) error {
+		_, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrUnauthorized)
+		}
+
+		u, err := h.storage.GetUser(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrOperational)
+		}
+		if u.ID == "" {
+			return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrNotFound)
+		}
+		user.ID = id
+		err = h.storage.UpdateUser(ctx, user)
+		if errors.Is(err, model.ErrConflict) {
+			return fmt.Errorf("%q: %q: %w", "Could not update user, username is taken.", err, model.ErrConflict)
+		}
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update user", err, model.ErrBadRequest)
+		}
+		if err := h.addEvent(ctx, model.EventUserUpdated, id, user); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update user.", err, model.ErrOperational)
+		}
+		return nil
+	})
 }
 
 func (h *handlersService) LoginUser(ctx context.Context, credentials model.Credentials) (model.Token, error) {
-	userID, err := h.AuthenticateUser(credentials)
+	userID, err := h.AuthenticateUser(ctx, credentials)
 	if err != nil {
 		return model.Token{}, fmt.Errorf("Login error: %q: %w", err, model.ErrUnauthorized)
 	}
@@ -147,7 +296,7 @@ func (h *handlersService) getUserFromContext(ctx context.Context) (string, error
 		return "", fmt.Errorf("%q", "Userid in context is empty.")
 	}
 
-	user, err := h.storage.GetUser(userid.(string))
+	user, err := h.storage.GetUser(ctx, userid.(string))
 	if err != nil {
 		return "", fmt.Errorf("%q", err)
 	}
@@ -158,20 +307,37 @@ func (h *handlersService) getUserFromContext(ctx context.Context) (string, error
 	return userid.(string), nil
 }
 
+// inTx runs fn with service bound to storage transaction.
+// Transaction is rolled back if fn returns error.
+func (h *handlersService) inTx(ctx context.Context, fn func(h *handlersService) error) error {
+	var fnErr error
+	err := h.storage.WithTx(ctx, func(tx storage.Storage) error {
+		txh := *h
+		txh.storage = tx
+		fnErr = fn(&txh)
+		return fnErr
+	})
+	if err != nil && fnErr == nil {
+		return fmt.Errorf("%q: %q: %w", "Could not run transaction.", err, model.ErrOperational)
+	}
+	return err
+}
+
 func (h *handlersService) HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 
 	return string(bytes), err
 }
 
-func (h *handlersService) AuthenticateUser(credentials model.Credentials) (string, error) {
+func (h *handlersService) AuthenticateUser(ctx context.Context, credentials model.Credentials) (string, error) {
 	filter := storage.UserFilter{UserName: credentials.UserName}
-	users, err := h.storage.GetAllUsers(filter)
+	users, err := h.storage.GetAllUsers(ctx, filter)
 	if err != nil {
 		return "", fmt.Errorf("%q", err)
 	}
 
-	if len(users) == 0 || !h.CheckPasswordHash(credentials.Password, users[0].Password) {
+	// usernames are unique, so more users mean broken storage
+	if len(users) != 1 || !h.CheckPasswordHash(credentials.Password, users[0].Password) {
 		return "", fmt.Errorf("%q", "Invalid user credentials.")
 	}
 	return users[0].ID, nil
@@ -208,40 +374,85 @@ func (h *handlersService) ValidateToken(ctx context.Context, tokenString string)
 }
 
 func (h *handlersService) AddTodo(ctx context.Context, todo model.TodoItem) (string, error) {
-	userid, err := h.getUserFromContext(ctx)
-	if err != nil {
-		return "", fmt.Errorf("%q: %q: %w", "Could not add todo.", err, model.ErrUnauthorized)
-	}
-	todo.UserID = userid
-	id, err := h.storage.AddItem(todo)
+	var id string
+	err := h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not add todo.", err, model.ErrUnauthorized)
+		}
+		todo.UserID = userid
+		if todo.AllDay {
+			if todo.Date, err = h.allDayDate(ctx, userid, todo.Date); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not add todo.", err, model.ErrOperational)
+			}
+		}
+		id, err = h.storage.AddItem(ctx, todo)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "Could not add todo", model.ErrBadRequest)
+		}
+
+		todo.ID = id
+		if err := h.publish(ctx, todoEvent(model.ActivityTodoCreated, model.TodoItem{}, todo)); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not add todo.", err, model.ErrOperational)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("%q: %w", "Could not add todo", model.ErrBadRequest)
+		return "", err
 	}
 	return id, nil
 }
 
-func (h *handlersService) GetTodos(ctx context.Context, filter storage.TodoFilter) ([]model.TodoItem, error) {
+func (h *handlersService) GetTodos(ctx context.Context, filter storage.TodoFilter, cursor string) (model.TodoPage, error) {
 	userid, err := h.getUserFromContext(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrUnauthorized)
+		return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrUnauthorized)
 	}
 	filter.UserID = userid
 
-	todos, err := h.storage.GetAllItems(filter)
+	if cursor != "" {
+		after, err := decodeTodoCursor(cursor)
+		if err != nil {
+			return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrBadRequest)
+		}
+		filter.After = &after
+	}
+
+	limit := pageLimit(filter.Limit)
+	filter.Limit = limit + 1
+
+	todos, err := h.storage.GetAllItems(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrOperational)
+		return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get all todos.", err, model.ErrOperational)
 	}
 
-	return todos, nil
+	page := model.TodoPage{Todos: todos}
+	if len(todos) > limit {
+		page.Todos = todos[:limit]
+		page.NextCursor = encodeTodoCursor(page.Todos[limit-1])
+	}
+	return page, nil
 }
 
-func (h *handlersService) GetTodo(ctx context.Context, id string) (model.TodoItem, error) {
+// GetTodo returns todo of current user with given fields loaded, empty fields load whole todo.
+// Version is always loaded, because it tags todo for conditional requests.
+func (h *handlersService) GetTodo(ctx context.Context, id string, fields []string) (model.TodoItem, error) {
 	userid, err := h.getUserFromContext(ctx)
 	if err != nil {
 		return model.TodoItem{}, fmt.Errorf("%q: %q: %w", "Could not get todo.", err, model.ErrUnauthorized)
 	}
 
-	todo, err := h.storage.GetItem(id)
+	var todo model.TodoItem
+	if len(fields) == 0 {
+		todo, err = h.storage.GetItem(ctx, id)
+	} else {
+		var todos []model.TodoItem
+		todos, err = h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: userid, IDs: []string{id}, Fields: storage.WithField(fields, "version")})
+		if len(todos) > 0 {
+			todo = todos[0]
+			todo.UserID = userid
+		}
+	}
 	if err != nil {
 		return model.TodoItem{}, fmt.Errorf("%q: %q: %w", "Could not get todo.", err, model.ErrOperational)
 	}
@@ -252,45 +463,71 @@ func (h *handlersService) GetTodo(ctx context.Context, id string) (model.TodoIte
 	return todo, nil
 }
 
-func (h *handlersService) DeleteTodo(ctx context.Context, id string) error {
-	userid, err := h.getUserFromContext(ctx)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrUnauthorized)
-	}
-
-	todo, err := h.storage.GetItem(id)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrOperational)
-	}
-	if todo.ID == "" || todo.UserID != userid {
-		return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrNotFound)
-	}
-
-	if err := h.storage.DeleteItem(id); err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not get tod.", err, model.ErrOperational)
-	}
-	return nil
+// DeleteTodo deletes todo if its version matches, version 0 deletes any version.
+func (h *handlersService) DeleteTodo(ctx context.Context, id string, version int) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrUnauthorized)
+		}
+
+		todo, err := h.storage.GetItem(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrOperational)
+		}
+		if todo.ID == "" || todo.UserID != userid {
+			return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrNotFound)
+		}
+		if version != 0 && version != todo.Version {
+			return fmt.Errorf("%q: %w", "Could not delete todo.", model.ErrVersionConflict)
+		}
+
+		if err := h.storage.DeleteItem(ctx, id); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not get tod.", err, model.ErrOperational)
+		}
+
+		if err := h.publish(ctx, todoEvent(model.ActivityTodoDeleted, todo, todo)); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete todo.", err, model.ErrOperational)
+		}
+		return nil
+	})
 }
 
+// UpdateTodo updates todo if its version matches todo.Version, version 0 updates any version.
 func (h *handlersService) UpdateTodo(ctx context.Context, id string, todo model.TodoItem) error {
-	userid, err := h.getUserFromContext(ctx)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrUnauthorized)
-	}
-
-	u, err := h.storage.GetItem(id)
-	if err != nil {
-		return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrOperational)
-	}
-	if u.ID == "" || u.UserID != userid {
-		return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrNotFound)
-	}
-
-	todo.ID = id
-	todo.UserID = userid
-	err = h.storage.UpdateItem(todo)
-	if err != nil {
-		return fmt.Errorf("%q: %w", "Could not update todo", model.ErrBadRequest)
-	}
-	return nil
+	return h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrUnauthorized)
+		}
+
+		u, err := h.storage.GetItem(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrOperational)
+		}
+		if u.ID == "" || u.UserID != userid {
+			return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrNotFound)
+		}
+		if todo.Version != 0 && todo.Version != u.Version {
+			return fmt.Errorf("%q: %w", "Could not update todo.", model.ErrVersionConflict)
+		}
+
+		todo.ID = id
+		todo.UserID = userid
+		todo.Position = u.Position
+		if todo.AllDay {
+			if todo.Date, err = h.allDayDate(ctx, userid, todo.Date); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrOperational)
+			}
+		}
+		err = h.storage.UpdateItem(ctx, todo)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "Could not update todo", model.ErrBadRequest)
+		}
+
+		if err := h.publish(ctx, todoEvent(todoActivityType(u, todo), u, todo)); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update todo.", err, model.ErrOperational)
+		}
+		return nil
+	})
 }
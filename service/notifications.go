@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+)
+
+func (h *handlersService) GetNotifications(ctx context.Context, filter storage.NotificationFilter, cursor string) (model.NotificationPage, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.NotificationPage{}, fmt.Errorf("%q: %q: %w", "Could not get notifications.", err, model.ErrUnauthorized)
+	}
+	filter.UserID = userid
+
+	if cursor != "" {
+		date, id, err := decodeCursor(cursor)
+		if err != nil {
+			return model.NotificationPage{}, fmt.Errorf("%q: %q: %w", "Could not get notifications.", err, model.ErrBadRequest)
+		}
+		filter.Before = &storage.Cursor{Date: date, ID: id}
+	}
+
+	limit := pageLimit(filter.Limit)
+	filter.Limit = limit + 1
+
+	notifications, err := h.storage.GetAllNotifications(ctx, filter)
+	if err != nil {
+		return model.NotificationPage{}, fmt.Errorf("%q: %q: %w", "Could not get notifications.", err, model.ErrOperational)
+	}
+
+	unread, err := h.storage.CountUnreadNotifications(ctx, userid)
+	if err != nil {
+		return model.NotificationPage{}, fmt.Errorf("%q: %q: %w", "Could not get notifications.", err, model.ErrOperational)
+	}
+
+	page := model.NotificationPage{Notifications: notifications, Unread: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		last := page.Notifications[limit-1]
+		page.NextCursor = encodeCursor(last.Date, last.ID)
+	}
+	return page, nil
+}
+
+func (h *handlersService) CountUnreadNotifications(ctx context.Context) (model.UnreadCount, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.UnreadCount{}, fmt.Errorf("%q: %q: %w", "Could not count notifications.", err, model.ErrUnauthorized)
+	}
+
+	unread, err := h.storage.CountUnreadNotifications(ctx, userid)
+	if err != nil {
+		return model.UnreadCount{}, fmt.Errorf("%q: %q: %w", "Could not count notifications.", err, model.ErrOperational)
+	}
+	return model.UnreadCount{Unread: unread}, nil
+}
+
+func (h *handlersService) ReadNotification(ctx context.Context, id string) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		userid, err := h.getUserFromContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not read notification.", err, model.ErrUnauthorized)
+		}
+
+		n, err := h.storage.GetNotification(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not read notification.", err, model.ErrOperational)
+		}
+		if n.ID == "" || n.UserID != userid {
+			return fmt.Errorf("%q: %w", "Could not read notification.", model.ErrNotFound)
+		}
+
+		if err := h.storage.MarkNotificationRead(ctx, id); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not read notification.", err, model.ErrOperational)
+		}
+		return nil
+	})
+}
+
+func (h *handlersService) ReadAllNotifications(ctx context.Context) error {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("%q: %q: %w", "Could not read notifications.", err, model.ErrUnauthorized)
+	}
+
+	if err := h.storage.MarkAllNotificationsRead(ctx, userid); err != nil {
+		return fmt.Errorf("%q: %q: %w", "Could not read notifications.", err, model.ErrOperational)
+	}
+	return nil
+}
+
+// PurgeNotifications deletes notifications older than configured retention.
+func (h *handlersService) PurgeNotifications(ctx context.Context) error {
+	if h.config.NotificationRetention <= 0 {
+		return nil
+	}
+
+	before := time.Now().UTC().Add(-h.config.NotificationRetention)
+	if err := h.storage.DeleteNotificationsBefore(ctx, before); err != nil {
+		return fmt.Errorf("%q: %q: %w", "Could not purge notifications.", err, model.ErrOperational)
+	}
+	return nil
+}
+
+// RemindDueTodos notifies users about their todos due within configured reminder lead.
+// Done todos are skipped and every todo is reminded once.
+func (h *handlersService) RemindDueTodos(ctx context.Context) error {
+	if h.config.ReminderLead <= 0 {
+		return nil
+	}
+
+	from := time.Now().UTC()
+	to := from.Add(h.config.ReminderLead)
+	users, err := h.storage.GetAllUsers(ctx, storage.UserFilter{Fields: []string{"id"}})
+	if err != nil {
+		return fmt.Errorf("%q: %q: %w", "Could not remind todos.", err, model.ErrOperational)
+	}
+	for _, u := range users {
+		todos, err := h.storage.GetAllItems(ctx, storage.TodoFilter{UserID: u.ID, FromDate: &from, ToDate: &to})
+		if err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not remind todos.", err, model.ErrOperational)
+		}
+		for _, todo := range todos {
+			if todo.Status == model.StatusDone {
+				continue
+			}
+			if err := h.remind(ctx, todo); err != nil {
+				return fmt.Errorf("%q: %q: %w", "Could not remind todos.", err, model.ErrOperational)
+			}
+		}
+	}
+	return nil
+}
+
+// remind notifies owner of todo unless it was already reminded.
+func (h *handlersService) remind(ctx context.Context, todo model.TodoItem) error {
+	reminded, err := h.storage.GetAllNotifications(ctx, storage.NotificationFilter{
+		UserID: todo.UserID,
+		Type:   model.NotificationReminder,
+		TodoID: todo.ID,
+		Limit:  1,
+	})
+	if err != nil || len(reminded) > 0 {
+		return err
+	}
+
+	message := fmt.Sprintf("%q is due at %s", todo.Name, todo.Date.Format("2006-01-02 15:04"))
+	if todo.AllDay {
+		message = fmt.Sprintf("%q is due on %s", todo.Name, todo.Date.Format("2006-01-02"))
+	}
+	_, err = h.storage.AddNotification(ctx, model.Notification{
+		Type:    model.NotificationReminder,
+		UserID:  todo.UserID,
+		TodoID:  todo.ID,
+		Message: message,
+	})
+	return err
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"todo/model"
+	"todo/storage"
+)
+
+func (h *handlersService) SearchTodos(ctx context.Context, search storage.TodoSearch) ([]model.TodoSearchResult, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not search todos.", err, model.ErrUnauthorized)
+	}
+
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return nil, fmt.Errorf("%q: %w", "Could not search todos: query is empty.", model.ErrBadRequest)
+	}
+	search.UserID = userid
+	search.Limit = pageLimit(search.Limit)
+
+	results, err := h.storage.SearchItems(ctx, search)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not search todos.", err, model.ErrOperational)
+	}
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/query"
+)
+
+const defaultUpcomingDays = 7
+
+func (h *handlersService) GetSmartList(ctx context.Context, list string, days int) ([]model.TodoItem, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get todo list.", err, model.ErrUnauthorized)
+	}
+
+	location, err := h.userLocation(ctx, userid)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get todo list.", err, model.ErrOperational)
+	}
+
+	if days == 0 {
+		days = defaultUpcomingDays
+	}
+	if days < 0 || days > maxRangeDays {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get todo list.", fmt.Sprintf("days must be between 1 and %d", maxRangeDays), model.ErrBadRequest)
+	}
+
+	today := storage.BucketStart(time.Now(), model.BucketDay, location)
+	tomorrow := today.AddDate(0, 0, 1)
+	horizon := tomorrow.AddDate(0, 0, days)
+
+	var from, to *time.Time
+	switch list {
+	case model.ListToday:
+		from, to = &today, &tomorrow
+	case model.ListUpcoming:
+		from, to = &tomorrow, &horizon
+	case model.ListOverdue:
+		to = &today
+	case model.ListSomeday:
+		from = &horizon
+	default:
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get todo list.", fmt.Sprintf("unknown list %q", list), model.ErrBadRequest)
+	}
+
+	filter := storage.TodoFilter{
+		UserID: userid,
+		Query:  &query.Comparison{Field: query.FieldStatus, Op: query.OpNe, Value: model.StatusDone},
+	}
+	// all-day todos are stored at midnight UTC of their date, so bounds are
+	// widened to cover them and todos are checked against local bounds below
+	if from != nil {
+		f := earliest(*from, model.AllDayDate(*from))
+		filter.FromDate = &f
+	}
+	if to != nil {
+		t := latest(*to, model.AllDayDate(*to))
+		filter.ToDate = &t
+	}
+
+	todos, err := h.storage.GetAllItems(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get todo list.", err, model.ErrOperational)
+	}
+
+	listed := make([]model.TodoItem, 0, len(todos))
+	for _, todo := range todos {
+		due := localDue(todo, location)
+		if (from == nil || !due.Before(*from)) && (to == nil || due.Before(*to)) {
+			listed = append(listed, todo)
+		}
+	}
+	sort.SliceStable(listed, func(a, b int) bool {
+		da, db := localDue(listed[a], location), localDue(listed[b], location)
+		if da.Equal(db) {
+			return listed[a].ID < listed[b].ID
+		}
+		return da.Before(db)
+	})
+	return listed, nil
+}
+
+// localDue returns moment todo is due in location.
+// All-day todos are due at local midnight of their date.
+func localDue(todo model.TodoItem, location *time.Location) time.Time {
+	if todo.AllDay {
+		d := todo.Date.UTC()
+		return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, location)
+	}
+	return todo.Date
+}
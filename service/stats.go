@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	defaultStatsDays = 30
+	maxRangeDays     = 366
+)
+
+func (h *handlersService) GetStats(ctx context.Context, from, to, bucket string) (model.Stats, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.Stats{}, fmt.Errorf("%q: %q: %w", "Could not get stats.", err, model.ErrUnauthorized)
+	}
+
+	location, err := h.userLocation(ctx, userid)
+	if err != nil {
+		return model.Stats{}, fmt.Errorf("%q: %q: %w", "Could not get stats.", err, model.ErrOperational)
+	}
+
+	if bucket == "" {
+		bucket = model.BucketDay
+	}
+	if bucket != model.BucketDay && bucket != model.BucketWeek {
+		return model.Stats{}, fmt.Errorf("%q: %q: %w", "Could not get stats.", fmt.Sprintf("unknown bucket %q", bucket), model.ErrBadRequest)
+	}
+
+	now := time.Now()
+	start, end, err := dateRange(from, to, now, defaultStatsDays, location)
+	if err != nil {
+		return model.Stats{}, fmt.Errorf("%q: %q: %w", "Could not get stats.", err, model.ErrBadRequest)
+	}
+
+	stats, err := h.storage.GetStats(ctx, storage.StatsFilter{
+		UserID:   userid,
+		From:     start,
+		To:       end,
+		Bucket:   bucket,
+		Location: location,
+		Now:      now,
+	})
+	if err != nil {
+		return model.Stats{}, fmt.Errorf("%q: %q: %w", "Could not get stats.", err, model.ErrOperational)
+	}
+
+	stats.From, stats.To, stats.Bucket = start, end, bucket
+	stats.Buckets = fillBuckets(stats.Buckets, start, end, bucket, location)
+	return stats, nil
+}
+
+// userLocation returns location of user, UTC if user has none.
+func (h *handlersService) userLocation(ctx context.Context, userid string) (*time.Location, error) {
+	user, err := h.storage.GetUser(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+	if user.Location.Location == nil {
+		return time.UTC, nil
+	}
+	return user.Location.Location, nil
+}
+
+// dateRange parses inclusive range of local dates like "2021-05-01" into [start, end).
+// Missing to means today, missing from means days before to.
+func dateRange(from, to string, now time.Time, days int, location *time.Location) (time.Time, time.Time, error) {
+	end := storage.BucketStart(now, model.BucketDay, location).AddDate(0, 0, 1)
+	if to != "" {
+		t, err := time.ParseInLocation(dateLayout, to, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %q", to)
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+
+	start := end.AddDate(0, 0, -days)
+	if from != "" {
+		t, err := time.ParseInLocation(dateLayout, from, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %q", from)
+		}
+		start = t
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date is after to date")
+	}
+	if start.AddDate(0, 0, maxRangeDays).Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range is longer than %d days", maxRangeDays)
+	}
+	return start, end, nil
+}
+
+// fillBuckets returns all buckets between start and end, missing ones are empty.
+func fillBuckets(buckets []model.StatsBucket, start, end time.Time, bucket string, location *time.Location) []model.StatsBucket {
+	found := map[int64]model.StatsBucket{}
+	for _, b := range buckets {
+		found[b.Start.Unix()] = b
+	}
+
+	step := 1
+	if bucket == model.BucketWeek {
+		step = 7
+	}
+
+	all := make([]model.StatsBucket, 0)
+	for s := storage.BucketStart(start, bucket, location); s.Before(end); s = s.AddDate(0, 0, step) {
+		b, ok := found[s.Unix()]
+		if !ok {
+			b = model.StatsBucket{Start: s}
+		}
+		all = append(all, b)
+	}
+	return all
+}
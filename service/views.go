@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/query"
+)
+
+func (h *handlersService) AddView(ctx context.Context, view model.View) (string, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%q: %q: %w", "Could not add view.", err, model.ErrUnauthorized)
+	}
+	if _, err := viewFilter(view); err != nil {
+		return "", fmt.Errorf("%q: %q: %w", "Could not add view.", err, model.ErrBadRequest)
+	}
+
+	view.UserID = userid
+	id, err := h.storage.AddView(ctx, view)
+	if err != nil {
+		return "", fmt.Errorf("%q: %q: %w", "Could not add view.", err, model.ErrOperational)
+	}
+	return id, nil
+}
+
+func (h *handlersService) GetViews(ctx context.Context) ([]model.View, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get views.", err, model.ErrUnauthorized)
+	}
+
+	views, err := h.storage.GetAllViews(ctx, userid)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %q: %w", "Could not get views.", err, model.ErrOperational)
+	}
+	return views, nil
+}
+
+func (h *handlersService) GetView(ctx context.Context, id string) (model.View, error) {
+	userid, err := h.getUserFromContext(ctx)
+	if err != nil {
+		return model.View{}, fmt.Errorf("%q: %q: %w", "Could not get view.", err, model.ErrUnauthorized)
+	}
+
+	view, err := h.storage.GetView(ctx, id)
+	if err != nil {
+		return model.View{}, fmt.Errorf("%q: %q: %w", "Could not get view.", err, model.ErrOperational)
+	}
+	if view.ID == "" || view.UserID != userid {
+		return model.View{}, fmt.Errorf("%q: %w", "Could not get view.", model.ErrNotFound)
+	}
+	return view, nil
+}
+
+func (h *handlersService) UpdateView(ctx context.Context, id string, view model.View) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		v, err := h.GetView(ctx, id)
+		if err != nil {
+			return fmt.Errorf("%q: %w", "Could not update view.", err)
+		}
+		if _, err := viewFilter(view); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update view.", err, model.ErrBadRequest)
+		}
+
+		view.ID = v.ID
+		view.UserID = v.UserID
+		if err := h.storage.UpdateView(ctx, view); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not update view.", err, model.ErrOperational)
+		}
+		return nil
+	})
+}
+
+func (h *handlersService) DeleteView(ctx context.Context, id string) error {
+	return h.inTx(ctx, func(h *handlersService) error {
+		if _, err := h.GetView(ctx, id); err != nil {
+			return fmt.Errorf("%q: %w", "Could not delete view.", err)
+		}
+
+		if err := h.storage.DeleteView(ctx, id); err != nil {
+			return fmt.Errorf("%q: %q: %w", "Could not delete view.", err, model.ErrOperational)
+		}
+		return nil
+	})
+}
+
+func (h *handlersService) GetViewTodos(ctx context.Context, id string, limit int, cursor string) (model.TodoPage, error) {
+	view, err := h.GetView(ctx, id)
+	if err != nil {
+		return model.TodoPage{}, fmt.Errorf("%q: %w", "Could not get view todos.", err)
+	}
+
+	filter, err := viewFilter(view)
+	if err != nil {
+		return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get view todos.", err, model.ErrBadRequest)
+	}
+	filter.UserID = view.UserID
+
+	if cursor != "" {
+		after, err := decodeTodoCursor(cursor)
+		if err != nil {
+			return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get view todos.", err, model.ErrBadRequest)
+		}
+		filter.After = &after
+	}
+
+	limit = pageLimit(limit)
+	filter.Limit = limit + 1
+
+	todos, err := h.storage.GetAllItems(ctx, filter)
+	if err != nil {
+		return model.TodoPage{}, fmt.Errorf("%q: %q: %w", "Could not get view todos.", err, model.ErrOperational)
+	}
+
+	page := model.TodoPage{Todos: todos}
+	if len(todos) > limit {
+		page.Todos = todos[:limit]
+		page.NextCursor = encodeTodoCursor(page.Todos[limit-1])
+	}
+	return page, nil
+}
+
+// viewFilter validates view and returns its filter.
+func viewFilter(view model.View) (storage.TodoFilter, error) {
+	if strings.TrimSpace(view.Name) == "" {
+		return storage.TodoFilter{}, fmt.Errorf("view name is empty")
+	}
+
+	filter := storage.TodoFilter{
+		Status:   view.Status,
+		FromDate: view.FromDate,
+		ToDate:   view.ToDate,
+	}
+	if view.Query != "" {
+		expr, err := query.Parse(view.Query)
+		if err != nil {
+			return storage.TodoFilter{}, err
+		}
+		filter.Query = expr
+	}
+
+	sort, err := storage.ParseSort(view.Sort)
+	if err != nil {
+		return storage.TodoFilter{}, err
+	}
+	filter.Sort = sort
+	return filter, nil
+}
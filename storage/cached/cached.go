@@ -0,0 +1,196 @@
+// Package cached provides storage decorator caching users and todos.
+package cached
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"todo/metrics"
+	"todo/model"
+	"todo/storage"
+)
+
+// Cached represents storage serving users and todos from LRU cache.
+// Entries are invalidated on writes made through Cached, writes made around it
+// are seen once entries expire after ttl.
+type Cached struct {
+	storage.Storage
+	users *lru
+	items *lru
+	tx    *invalidations // nil outside of transaction
+}
+
+// invalidations collects keys written in transaction, they are invalidated again after it ends,
+// so values read by others while it was running are not kept.
+type invalidations struct {
+	mu       sync.Mutex
+	users    []string
+	items    []string
+	allItems bool
+}
+
+// NewCachedStorage returns storage caching up to size users and size todos for ttl.
+func NewCachedStorage(s storage.Storage, size int, ttl time.Duration) *Cached {
+	return &Cached{Storage: s, users: newLRU(size, ttl), items: newLRU(size, ttl)}
+}
+
+// GetItem gets todo from cache, falling back to storage.
+// Reads in transaction go to storage, so they see uncommitted changes and take locks.
+func (c *Cached) GetItem(ctx context.Context, id string) (model.TodoItem, error) {
+	if c.tx != nil {
+		return c.Storage.GetItem(ctx, id)
+	}
+	v, gen, ok := c.items.get(id)
+	if ok {
+		metrics.CacheRequests.WithLabelValues("item", "hit").Inc()
+		return v.(model.TodoItem), nil
+	}
+	metrics.CacheRequests.WithLabelValues("item", "miss").Inc()
+
+	todo, err := c.Storage.GetItem(ctx, id)
+	if err == nil && todo.ID != "" {
+		c.items.add(id, todo, gen)
+	}
+	return todo, err
+}
+
+// GetUser gets user from cache, falling back to storage.
+func (c *Cached) GetUser(ctx context.Context, id string) (model.User, error) {
+	if c.tx != nil {
+		return c.Storage.GetUser(ctx, id)
+	}
+	v, gen, ok := c.users.get(id)
+	if ok {
+		metrics.CacheRequests.WithLabelValues("user", "hit").Inc()
+		return v.(model.User), nil
+	}
+	metrics.CacheRequests.WithLabelValues("user", "miss").Inc()
+
+	user, err := c.Storage.GetUser(ctx, id)
+	if err == nil && user.ID != "" {
+		c.users.add(id, user, gen)
+	}
+	return user, err
+}
+
+// UpdateItem updates todo and invalidates it.
+func (c *Cached) UpdateItem(ctx context.Context, item model.TodoItem) error {
+	defer c.invalidateItem(item.ID)
+	return c.Storage.UpdateItem(ctx, item)
+}
+
+// DeleteItem deletes todo and invalidates it.
+func (c *Cached) DeleteItem(ctx context.Context, id string) error {
+	defer c.invalidateItem(id)
+	return c.Storage.DeleteItem(ctx, id)
+}
+
+// UpdateItems updates todos and invalidates them.
+func (c *Cached) UpdateItems(ctx context.Context, items []model.TodoItem) ([]storage.ItemResult, error) {
+	defer func() {
+		for _, item := range items {
+			c.invalidateItem(item.ID)
+		}
+	}()
+	return c.Storage.UpdateItems(ctx, items)
+}
+
+// UpdateItemPositions sets positions of todos and invalidates them.
+func (c *Cached) UpdateItemPositions(ctx context.Context, items []model.TodoItem) error {
+	defer func() {
+		for _, item := range items {
+			c.invalidateItem(item.ID)
+		}
+	}()
+	return c.Storage.UpdateItemPositions(ctx, items)
+}
+
+// DeleteItems deletes todos and invalidates them.
+func (c *Cached) DeleteItems(ctx context.Context, ids []string) ([]storage.ItemResult, error) {
+	defer func() {
+		for _, id := range ids {
+			c.invalidateItem(id)
+		}
+	}()
+	return c.Storage.DeleteItems(ctx, ids)
+}
+
+// ReassignItems moves todos of one user to another and invalidates all todos.
+func (c *Cached) ReassignItems(ctx context.Context, fromUserID, toUserID string) error {
+	defer c.invalidateAllItems()
+	return c.Storage.ReassignItems(ctx, fromUserID, toUserID)
+}
+
+// UpdateUser updates user and invalidates it together with all todos,
+// because todo dates depend on users location.
+func (c *Cached) UpdateUser(ctx context.Context, user model.User) error {
+	defer c.invalidateAllItems()
+	defer c.invalidateUser(user.ID)
+	return c.Storage.UpdateUser(ctx, user)
+}
+
+// DeleteUser deletes user and invalidates it together with all todos.
+func (c *Cached) DeleteUser(ctx context.Context, id string) error {
+	defer c.invalidateAllItems()
+	defer c.invalidateUser(id)
+	return c.Storage.DeleteUser(ctx, id)
+}
+
+// WithTx runs fn in transaction of underlying storage.
+// Keys written in transaction are invalidated once more after it ends.
+func (c *Cached) WithTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	if c.tx != nil {
+		return c.Storage.WithTx(ctx, func(tx storage.Storage) error {
+			return fn(&Cached{Storage: tx, users: c.users, items: c.items, tx: c.tx})
+		})
+	}
+
+	inv := &invalidations{}
+	defer c.invalidateAll(inv)
+	return c.Storage.WithTx(ctx, func(tx storage.Storage) error {
+		return fn(&Cached{Storage: tx, users: c.users, items: c.items, tx: inv})
+	})
+}
+
+func (c *Cached) invalidateItem(id string) {
+	c.items.remove(id)
+	if c.tx != nil {
+		c.tx.mu.Lock()
+		c.tx.items = append(c.tx.items, id)
+		c.tx.mu.Unlock()
+	}
+}
+
+func (c *Cached) invalidateAllItems() {
+	c.items.purge()
+	if c.tx != nil {
+		c.tx.mu.Lock()
+		c.tx.allItems = true
+		c.tx.mu.Unlock()
+	}
+}
+
+func (c *Cached) invalidateUser(id string) {
+	c.users.remove(id)
+	if c.tx != nil {
+		c.tx.mu.Lock()
+		c.tx.users = append(c.tx.users, id)
+		c.tx.mu.Unlock()
+	}
+}
+
+func (c *Cached) invalidateAll(inv *invalidations) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for _, id := range inv.users {
+		c.users.remove(id)
+	}
+	if inv.allItems {
+		c.items.purge()
+		return
+	}
+	for _, id := range inv.items {
+		c.items.remove(id)
+	}
+}
@@ -0,0 +1,125 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/inmemory"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// counting counts reads reaching underlying storage.
+type counting struct {
+	*inmemory.InMemory
+	items, users int
+}
+
+func (c *counting) GetItem(ctx context.Context, id string) (model.TodoItem, error) {
+	c.items++
+	return c.InMemory.GetItem(ctx, id)
+}
+
+func (c *counting) GetUser(ctx context.Context, id string) (model.User, error) {
+	c.users++
+	return c.InMemory.GetUser(ctx, id)
+}
+
+func TestCached(t *testing.T) {
+	ctx := context.Background()
+	s := &counting{InMemory: inmemory.NewInMemoryStorage()}
+	c := NewCachedStorage(s, 2, time.Minute)
+
+	userID, err := c.AddUser(ctx, model.User{UserName: "roxy"})
+	assert.NoError(t, err)
+	todoID, err := c.AddItem(ctx, model.TodoItem{Name: "a", UserID: userID})
+	assert.NoError(t, err)
+
+	t.Run("reads are served from cache", func(t *testing.T) {
+		for n := 0; n < 3; n++ {
+			todo, err := c.GetItem(ctx, todoID)
+			assert.NoError(t, err)
+			assert.Equal(t, "a", todo.Name)
+			user, err := c.GetUser(ctx, userID)
+			assert.NoError(t, err)
+			assert.Equal(t, "roxy", user.UserName)
+		}
+		assert.Equal(t, 1, s.items)
+		assert.Equal(t, 1, s.users)
+	})
+
+	t.Run("missing records are not cached", func(t *testing.T) {
+		s.items = 0
+		c.GetItem(ctx, "missing")
+		c.GetItem(ctx, "missing")
+		assert.Equal(t, 2, s.items)
+	})
+
+	t.Run("writes invalidate", func(t *testing.T) {
+		assert.NoError(t, c.UpdateItem(ctx, model.TodoItem{ID: todoID, Name: "b", UserID: userID}))
+		todo, err := c.GetItem(ctx, todoID)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", todo.Name)
+
+		assert.NoError(t, c.UpdateUser(ctx, model.User{ID: userID, UserName: "proxy"}))
+		user, err := c.GetUser(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "proxy", user.UserName)
+	})
+
+	t.Run("transactions", func(t *testing.T) {
+		c.GetItem(ctx, todoID)
+		err := c.WithTx(ctx, func(tx storage.Storage) error {
+			if err := tx.UpdateItem(ctx, model.TodoItem{ID: todoID, Name: "c", UserID: userID}); err != nil {
+				return err
+			}
+			todo, err := tx.GetItem(ctx, todoID)
+			assert.NoError(t, err)
+			assert.Equal(t, "c", todo.Name)
+			return errors.New("failed")
+		})
+		assert.Error(t, err)
+
+		todo, err := c.GetItem(ctx, todoID)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", todo.Name, "rolled back change is not cached")
+
+		assert.NoError(t, c.WithTx(ctx, func(tx storage.Storage) error {
+			return tx.DeleteItem(ctx, todoID)
+		}))
+		todo, err = c.GetItem(ctx, todoID)
+		assert.NoError(t, err)
+		assert.Equal(t, "", todo.ID)
+	})
+}
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	c := newLRU(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	_, gen, _ := c.get("a")
+	c.add("a", 1, gen)
+	c.add("b", 2, gen)
+	c.get("a")
+	c.add("c", 3, gen)
+	_, _, ok := c.get("b")
+	assert.False(t, ok, "least recently used is evicted")
+	v, _, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	now = now.Add(time.Minute)
+	_, _, ok = c.get("a")
+	assert.False(t, ok, "expired")
+
+	_, gen, _ = c.get("d")
+	c.remove("c")
+	c.add("d", 4, gen)
+	_, _, ok = c.get("d")
+	assert.False(t, ok, "value loaded before invalidation is dropped")
+}
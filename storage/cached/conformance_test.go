@@ -0,0 +1,16 @@
+package cached
+
+import (
+	"testing"
+	"time"
+
+	"todo/storage"
+	"todo/storage/inmemory"
+	"todo/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return NewCachedStorage(inmemory.NewInMemoryStorage(), 100, time.Minute)
+	})
+}
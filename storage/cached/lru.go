@@ -0,0 +1,88 @@
+package cached
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is least recently used cache with entries expiring after ttl.
+type lru struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	ll      *list.List // front is most recently used
+	entries map[string]*list.Element
+	gen     uint64 // incremented on every invalidation
+	now     func() time.Time
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{size: size, ttl: ttl, ll: list.New(), entries: map[string]*list.Element{}, now: time.Now}
+}
+
+// get returns cached value and current generation, generation is passed to add after value is loaded.
+func (c *lru) get(key string) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, c.gen, false
+	}
+	e := el.Value.(*entry)
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		c.ll.Remove(el)
+		delete(c.entries, key)
+		return nil, c.gen, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, c.gen, true
+}
+
+// add caches value loaded at generation gen.
+// Value is dropped if cache was invalidated since, because it may be already stale.
+func (c *lru) add(key string, value interface{}, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		el.Value = &entry{key: key, value: value, expires: expires}
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&entry{key: key, value: value, expires: expires})
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// remove invalidates single key.
+func (c *lru) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	if el, ok := c.entries[key]; ok {
+		c.ll.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+// purge invalidates all keys.
+func (c *lru) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.ll.Init()
+	c.entries = map[string]*list.Element{}
+}
@@ -0,0 +1,20 @@
+package embedded
+
+import (
+	"path/filepath"
+	"testing"
+
+	"todo/storage"
+	"todo/storage/storagetest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := Open(filepath.Join(t.TempDir(), "todo.db"))
+		assert.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return db
+	})
+}
@@ -0,0 +1,197 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/inmemory"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Embedded represents storage kept in single bbolt file.
+// All records are loaded into memory on open and served from there,
+// every change is written to the file before it is applied in memory.
+type Embedded struct {
+	*inmemory.InMemory
+	db      *bolt.DB
+	version int
+	current *bolt.Tx // transaction in progress, guarded by memory write lock
+}
+
+// recordTypes maps buckets to types of records stored in them.
+var recordTypes = map[string]reflect.Type{
+	inmemory.KindTodo:         reflect.TypeOf(model.TodoItem{}),
+	inmemory.KindUser:         reflect.TypeOf(model.User{}),
+	inmemory.KindActivity:     reflect.TypeOf(model.Activity{}),
+	inmemory.KindNotification: reflect.TypeOf(model.Notification{}),
+	inmemory.KindBoard:        reflect.TypeOf(model.Board{}),
+	inmemory.KindView:         reflect.TypeOf(model.View{}),
+	inmemory.KindEvent:        reflect.TypeOf(model.Event{}),
+}
+
+// Open opens storage file, creating it if needed, and migrates its schema.
+func Open(path string) (*Embedded, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to open: %v", err)
+	}
+
+	e := &Embedded{db: db}
+	e.InMemory = inmemory.NewJournaledStorage(e.journal)
+	if e.version, err = migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := e.load(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// Close closes storage file.
+func (e *Embedded) Close() error {
+	return e.db.Close()
+}
+
+// SchemaVersion returns schema version of storage file.
+func (e *Embedded) SchemaVersion() int {
+	return e.version
+}
+
+// WithTx runs fn in memory transaction backed by single bolt transaction.
+func (e *Embedded) WithTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	return e.InMemory.WithTx(ctx, func(tx storage.Storage) error {
+		btx, err := e.db.Begin(true)
+		if err != nil {
+			return fmt.Errorf("Unable to begin transaction: %v", err)
+		}
+		e.current = btx
+		defer func() {
+			e.current = nil
+			btx.Rollback()
+		}()
+
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := btx.Commit(); err != nil {
+			return fmt.Errorf("Unable to commit transaction: %v", err)
+		}
+		return nil
+	})
+}
+
+// DeleteUser deletes user with all its records in one transaction.
+func (e *Embedded) DeleteUser(ctx context.Context, id string) error {
+	return e.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.DeleteUser(ctx, id)
+	})
+}
+
+// AddItems adds todos in one transaction.
+func (e *Embedded) AddItems(ctx context.Context, items []model.TodoItem) (results []storage.ItemResult, err error) {
+	err = e.WithTx(ctx, func(tx storage.Storage) error {
+		results, err = tx.AddItems(ctx, items)
+		return err
+	})
+	return results, err
+}
+
+// UpdateItems updates todos in one transaction.
+func (e *Embedded) UpdateItems(ctx context.Context, items []model.TodoItem) (results []storage.ItemResult, err error) {
+	err = e.WithTx(ctx, func(tx storage.Storage) error {
+		results, err = tx.UpdateItems(ctx, items)
+		return err
+	})
+	return results, err
+}
+
+// DeleteItems deletes todos in one transaction.
+func (e *Embedded) DeleteItems(ctx context.Context, ids []string) (results []storage.ItemResult, err error) {
+	err = e.WithTx(ctx, func(tx storage.Storage) error {
+		results, err = tx.DeleteItems(ctx, ids)
+		return err
+	})
+	return results, err
+}
+
+// UpdateItemPositions sets positions of todos in one transaction.
+func (e *Embedded) UpdateItemPositions(ctx context.Context, items []model.TodoItem) error {
+	return e.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.UpdateItemPositions(ctx, items)
+	})
+}
+
+// ReassignItems moves todos of one user to another in one transaction.
+func (e *Embedded) ReassignItems(ctx context.Context, fromUserID, toUserID string) error {
+	return e.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.ReassignItems(ctx, fromUserID, toUserID)
+	})
+}
+
+// MarkAllNotificationsRead marks all notifications of user as read in one transaction.
+func (e *Embedded) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	return e.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.MarkAllNotificationsRead(ctx, userID)
+	})
+}
+
+// DeleteNotificationsBefore deletes notifications older than date in one transaction.
+func (e *Embedded) DeleteNotificationsBefore(ctx context.Context, date time.Time) error {
+	return e.WithTx(ctx, func(tx storage.Storage) error {
+		return tx.DeleteNotificationsBefore(ctx, date)
+	})
+}
+
+// journal writes changed record to file.
+func (e *Embedded) journal(kind, id string, record interface{}) error {
+	if e.current != nil {
+		return put(e.current, kind, id, record)
+	}
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return put(tx, kind, id, record)
+	})
+}
+
+func put(tx *bolt.Tx, kind, id string, record interface{}) error {
+	b := tx.Bucket([]byte(kind))
+	if b == nil {
+		return fmt.Errorf("bucket %s does not exist", kind)
+	}
+	if record == nil {
+		return b.Delete([]byte(id))
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(record); err != nil {
+		return err
+	}
+	return b.Put([]byte(id), buf.Bytes())
+}
+
+// load reads all records from file into memory.
+func (e *Embedded) load() error {
+	return e.db.View(func(tx *bolt.Tx) error {
+		for kind, typ := range recordTypes {
+			err := tx.Bucket([]byte(kind)).ForEach(func(k, v []byte) error {
+				record := reflect.New(typ)
+				if err := gob.NewDecoder(bytes.NewReader(v)).Decode(record.Interface()); err != nil {
+					return fmt.Errorf("Unable to decode %s %s: %v", kind, k, err)
+				}
+				return e.Restore(record.Elem().Interface())
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
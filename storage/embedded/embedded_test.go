@@ -0,0 +1,161 @@
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/inmemory"
+
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestEmbedded(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todo.db")
+	l, _ := time.LoadLocation("America/New_York")
+
+	db, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), db.SchemaVersion())
+
+	userID, err := db.AddUser(ctx, model.User{UserName: "Roxy", Password: "hash", Location: model.CustomLocation{Location: l}})
+	assert.NoError(t, err)
+	todoID, err := db.AddItem(ctx, model.TodoItem{Name: "Pay invoice", UserID: userID})
+	assert.NoError(t, err)
+	deletedID, err := db.AddItem(ctx, model.TodoItem{Name: "Deleted", UserID: userID})
+	assert.NoError(t, err)
+	assert.NoError(t, db.DeleteItem(ctx, deletedID))
+	_, err = db.AddNotification(ctx, model.Notification{UserID: userID, Message: "hello"})
+	assert.NoError(t, err)
+	assert.NoError(t, db.MarkAllNotificationsRead(ctx, userID))
+	viewID, err := db.AddView(ctx, model.View{Name: "open", Status: "new", UserID: userID})
+	assert.NoError(t, err)
+	eventID, err := db.AddEvent(ctx, model.Event{Type: model.EventTodoCreated, UserID: userID, Payload: []byte(`{}`)})
+	assert.NoError(t, err)
+
+	failed := errors.New("failed")
+	err = db.WithTx(ctx, func(tx storage.Storage) error {
+		if _, err := tx.AddItem(ctx, model.TodoItem{Name: "rolled back", UserID: userID}); err != nil {
+			return err
+		}
+		return failed
+	})
+	assert.Equal(t, failed, err)
+
+	err = db.WithTx(ctx, func(tx storage.Storage) error {
+		_, err := tx.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: userID, TodoID: todoID})
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	db, err = Open(path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	user, err := db.GetUser(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", user.Password)
+	assert.Equal(t, l.String(), user.Location.String())
+
+	todos, err := db.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, todos, 1)
+	assert.Equal(t, todoID, todos[0].ID)
+	assert.Equal(t, l, todos[0].Date.Location())
+
+	results, err := db.SearchItems(ctx, storage.TodoSearch{Query: "invoice", UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	unread, err := db.CountUnreadNotifications(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unread)
+
+	activities, err := db.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+
+	view, err := db.GetView(ctx, viewID)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, view.UserID)
+
+	events, err := db.GetEvents(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, eventID, events[0].ID)
+
+	nextID, err := db.AddEvent(ctx, model.Event{Type: model.EventTodoUpdated, UserID: userID, Payload: []byte(`{}`), Date: events[0].Date})
+	assert.NoError(t, err)
+	events, err = db.GetEvents(ctx, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, nextID, events[1].ID)
+		assert.Greater(t, events[1].Seq, events[0].Seq, "seq continues after reopening")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.db")
+	db, err := bolt.Open(path, 0600, nil)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	version, err := migrate(db)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), version)
+
+	version, err = migrate(db)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations), version)
+
+	// todo written before versions and completion times were introduced
+	created := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+	err = db.Update(func(tx *bolt.Tx) error {
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, 1)
+		if err := tx.Bucket(metaBucket).Put(versionKey, v); err != nil {
+			return err
+		}
+		if err := put(tx, inmemory.KindActivity, "a1", model.Activity{ID: "a1", Type: model.ActivityTodoCreated, TodoID: "1", Date: created}); err != nil {
+			return err
+		}
+		if err := put(tx, inmemory.KindActivity, "a2", model.Activity{ID: "a2", Type: model.ActivityTodoCompleted, TodoID: "1", Date: created.Add(time.Hour)}); err != nil {
+			return err
+		}
+		return put(tx, inmemory.KindTodo, "1", model.TodoItem{ID: "1", Name: "old", Status: model.StatusDone})
+	})
+	assert.NoError(t, err)
+	_, err = migrate(db)
+	assert.NoError(t, err)
+	err = db.View(func(tx *bolt.Tx) error {
+		todo := model.TodoItem{}
+		if err := gob.NewDecoder(bytes.NewReader(tx.Bucket([]byte(inmemory.KindTodo)).Get([]byte("1")))).Decode(&todo); err != nil {
+			return err
+		}
+		assert.Equal(t, 1, todo.Version)
+		assert.True(t, created.Equal(todo.Created))
+		assert.True(t, created.Add(time.Hour).Equal(todo.Completed))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(len(migrations)+1))
+		return tx.Bucket(metaBucket).Put(versionKey, v)
+	})
+	assert.NoError(t, err)
+
+	_, err = migrate(db)
+	assert.Error(t, err)
+}
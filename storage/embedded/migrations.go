@@ -0,0 +1,144 @@
+package embedded
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage/inmemory"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metaBucket = []byte("meta")
+	versionKey = []byte("schema_version")
+)
+
+// migrations upgrade storage file schema, n-th migration brings schema to version n+1.
+// Migrations are append only, applied ones must never change.
+var migrations = []func(tx *bolt.Tx) error{
+	// 1: record buckets
+	func(tx *bolt.Tx) error {
+		for _, kind := range []string{
+			inmemory.KindTodo, inmemory.KindUser, inmemory.KindActivity,
+			inmemory.KindNotification, inmemory.KindBoard, inmemory.KindView,
+		} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(kind)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	// 2: todo versions start at 1
+	func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(inmemory.KindTodo))
+		todos := map[string]model.TodoItem{}
+		err := b.ForEach(func(k, v []byte) error {
+			todo := model.TodoItem{}
+			if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&todo); err != nil {
+				return err
+			}
+			if todo.Version == 0 {
+				todo.Version = 1
+				todos[string(k)] = todo
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, todo := range todos {
+			if err := put(tx, inmemory.KindTodo, id, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	// 3: event outbox
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(inmemory.KindEvent))
+		return err
+	},
+	// 4: todo creation and completion times, taken from activity feed
+	func(tx *bolt.Tx) error {
+		created := map[string]time.Time{}
+		completed := map[string]time.Time{}
+		err := tx.Bucket([]byte(inmemory.KindActivity)).ForEach(func(k, v []byte) error {
+			a := model.Activity{}
+			if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&a); err != nil {
+				return err
+			}
+			switch a.Type {
+			case model.ActivityTodoCreated:
+				created[a.TodoID] = a.Date
+			case model.ActivityTodoCompleted:
+				if a.Date.After(completed[a.TodoID]) {
+					completed[a.TodoID] = a.Date
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		b := tx.Bucket([]byte(inmemory.KindTodo))
+		todos := map[string]model.TodoItem{}
+		err = b.ForEach(func(k, v []byte) error {
+			todo := model.TodoItem{}
+			if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&todo); err != nil {
+				return err
+			}
+			todo.Created = created[todo.ID]
+			if todo.Status == model.StatusDone {
+				todo.Completed = completed[todo.ID]
+			}
+			todos[string(k)] = todo
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for id, todo := range todos {
+			if err := put(tx, inmemory.KindTodo, id, todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// migrate applies pending migrations in one transaction and returns current schema version.
+func migrate(db *bolt.DB) (int, error) {
+	version := 0
+	err := db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if v := meta.Get(versionKey); v != nil {
+			version = int(binary.BigEndian.Uint64(v))
+		}
+		if version > len(migrations) {
+			return fmt.Errorf("schema version %d is newer than supported %d", version, len(migrations))
+		}
+
+		for ; version < len(migrations); version++ {
+			if err := migrations[version](tx); err != nil {
+				return fmt.Errorf("migration %d: %v", version+1, err)
+			}
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(version))
+		return meta.Put(versionKey, v)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Unable to migrate: %v", err)
+	}
+	return version, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TodoFields lists todo fields which can be requested in sparse fieldsets.
+var TodoFields = []string{"id", "name", "date", "allday", "status", "position", "version"}
+
+// UserFields lists user fields which can be requested in sparse fieldsets.
+var UserFields = []string{"id", "username", "firstname", "lastname", "location"}
+
+// PublicUserFields lists user fields shown in user directory.
+var PublicUserFields = []string{"id", "username", "firstname", "lastname"}
+
+// ParseFields parses comma separated list of fields like "id,name,status".
+// Empty spec means all fields and returns nil.
+func ParseFields(spec string, allowed []string) ([]string, error) {
+	var fields []string
+	if strings.TrimSpace(spec) == "" {
+		return fields, nil
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		field := strings.ToLower(strings.TrimSpace(part))
+		if !containsField(allowed, field) {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// SelectFields returns todo fields storage has to load for filter.
+// Besides requested fields it keeps id and sort fields needed for paging.
+// Nil means all fields.
+func (f TodoFilter) SelectFields() []string {
+	if len(f.Fields) == 0 {
+		return nil
+	}
+	fields := WithField(f.Fields, "id")
+	for _, s := range f.SortFields() {
+		fields = WithField(fields, s.Field)
+	}
+	if containsField(fields, "date") {
+		// all-day flag tells how date is converted to users location
+		fields = WithField(fields, "allday")
+	}
+	return fields
+}
+
+// SelectFields returns user fields storage has to load for filter.
+// Nil means all fields.
+func (f UserFilter) SelectFields() []string {
+	if len(f.Fields) == 0 {
+		return nil
+	}
+	return WithField(f.Fields, "id")
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// WithField returns fields with field appended unless it is already there.
+// Fields are copied, so the argument is never changed.
+func WithField(fields []string, field string) []string {
+	if containsField(fields, field) {
+		return fields
+	}
+	return append(append([]string{}, fields...), field)
+}
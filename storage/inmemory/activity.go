@@ -0,0 +1,73 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo/model"
+	"todo/storage"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddActivity adds activity to memory.
+func (i *InMemory) AddActivity(ctx context.Context, activity model.Activity) (string, error) {
+	defer i.lock()()
+	activity.ID = uuid.NewV4().String()
+	if activity.Date.IsZero() {
+		activity.Date = time.Now().UTC()
+	} else {
+		activity.Date = activity.Date.UTC()
+	}
+	if err := i.write(KindActivity, activity.ID, activity); err != nil {
+		return "", err
+	}
+	i.activities = append(i.activities, activity)
+	return activity.ID, nil
+}
+
+// GetAllActivities gets activities from memory, newest first.
+func (i *InMemory) GetAllActivities(ctx context.Context, filter storage.ActivityFilter) ([]model.Activity, error) {
+	defer i.rlock()()
+	arr := make([]model.Activity, 0)
+	for _, value := range i.activities {
+		if activityFiltered(filter, value) {
+			arr = append(arr, value)
+		}
+	}
+
+	sort.Slice(arr, func(a, b int) bool {
+		return recordBefore(arr[b].Date, arr[b].ID, arr[a].Date, arr[a].ID)
+	})
+
+	if filter.Limit > 0 && len(arr) > filter.Limit {
+		arr = arr[:filter.Limit]
+	}
+	return arr, nil
+}
+
+func activityFiltered(filter storage.ActivityFilter, a model.Activity) bool {
+	if !useridOk(filter.UserID, a.UserID) {
+		return false
+	}
+	if filter.Before != nil && !recordBefore(a.Date, a.ID, filter.Before.Date, filter.Before.ID) {
+		return false
+	}
+	if len(filter.Types) == 0 {
+		return true
+	}
+	for _, t := range filter.Types {
+		if t == a.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// recordBefore reports whether record at (date, id) is older than cursor position.
+func recordBefore(date time.Time, id string, cursorDate time.Time, cursorID string) bool {
+	if date.Equal(cursorDate) {
+		return id < cursorID
+	}
+	return date.Before(cursorDate)
+}
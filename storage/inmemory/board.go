@@ -0,0 +1,34 @@
+package inmemory
+
+import (
+	"context"
+	"todo/model"
+)
+
+// GetBoard gets board columns configuration of user from memory.
+func (i *InMemory) GetBoard(ctx context.Context, userID string) (model.Board, error) {
+	defer i.rlock()()
+	board := model.Board{UserID: userID, Columns: []model.BoardColumn{}}
+	board.Columns = append(board.Columns, i.boards[userID].Columns...)
+	return board, nil
+}
+
+// LockBoard does nothing, transactions in memory never interleave.
+func (i *InMemory) LockBoard(ctx context.Context, userID string) error {
+	return nil
+}
+
+// UpdateBoard replaces board columns configuration of user in memory.
+func (i *InMemory) UpdateBoard(ctx context.Context, board model.Board) error {
+	defer i.lock()()
+	columns := make([]model.BoardColumn, 0, len(board.Columns))
+	for _, column := range board.Columns {
+		columns = append(columns, model.BoardColumn{Status: column.Status, WIPLimit: column.WIPLimit})
+	}
+	board = model.Board{UserID: board.UserID, Columns: columns}
+	if err := i.write(KindBoard, board.UserID, board); err != nil {
+		return err
+	}
+	i.boards[board.UserID] = board
+	return nil
+}
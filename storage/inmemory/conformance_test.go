@@ -0,0 +1,14 @@
+package inmemory
+
+import (
+	"testing"
+
+	"todo/storage"
+	"todo/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return NewInMemoryStorage()
+	})
+}
@@ -0,0 +1,64 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo/model"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddEvent adds event to outbox in memory.
+// Like a database sequence, event seq is not reused when transaction is rolled back.
+func (i *InMemory) AddEvent(ctx context.Context, e model.Event) (string, error) {
+	defer i.lock()()
+	i.eventSeq++
+	e.ID = uuid.NewV4().String()
+	e.Seq = i.eventSeq
+	if e.Date.IsZero() {
+		e.Date = time.Now().UTC()
+	} else {
+		e.Date = e.Date.UTC()
+	}
+	if err := i.write(KindEvent, e.ID, e); err != nil {
+		return "", err
+	}
+	i.events[e.ID] = e
+	return e.ID, nil
+}
+
+// GetEvents gets undelivered events from memory in order they were added.
+func (i *InMemory) GetEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	defer i.rlock()()
+	arr := make([]model.Event, 0, len(i.events))
+	for _, e := range i.events {
+		arr = append(arr, e)
+	}
+
+	sort.Slice(arr, func(a, b int) bool {
+		return arr[a].Seq < arr[b].Seq
+	})
+
+	if limit > 0 && len(arr) > limit {
+		arr = arr[:limit]
+	}
+	return arr, nil
+}
+
+// restoreEventSeq continues event seq after events restored from journal or snapshot.
+func (i *InMemory) restoreEventSeq(e model.Event) {
+	if e.Seq > i.eventSeq {
+		i.eventSeq = e.Seq
+	}
+}
+
+// DeleteEvent deletes event from memory.
+func (i *InMemory) DeleteEvent(ctx context.Context, id string) error {
+	defer i.lock()()
+	if err := i.write(KindEvent, id, nil); err != nil {
+		return err
+	}
+	delete(i.events, id)
+	return nil
+}
@@ -0,0 +1,47 @@
+package inmemory
+
+import "todo/model"
+
+// projectItem returns copy of todo with only given fields set.
+func projectItem(t model.TodoItem, fields []string) model.TodoItem {
+	p := model.TodoItem{}
+	for _, f := range fields {
+		switch f {
+		case "id":
+			p.ID = t.ID
+		case "name":
+			p.Name = t.Name
+		case "date":
+			p.Date = t.Date
+		case "allday":
+			p.AllDay = t.AllDay
+		case "status":
+			p.Status = t.Status
+		case "position":
+			p.Position = t.Position
+		case "version":
+			p.Version = t.Version
+		}
+	}
+	return p
+}
+
+// projectUser returns copy of user with only given fields set.
+func projectUser(u model.User, fields []string) model.User {
+	p := model.User{}
+	for _, f := range fields {
+		switch f {
+		case "id":
+			p.ID = u.ID
+		case "username":
+			p.UserName = u.UserName
+		case "firstname":
+			p.FirstName = u.FirstName
+		case "lastname":
+			p.LastName = u.LastName
+		case "location":
+			p.Location = u.Location
+		}
+	}
+	return p
+}
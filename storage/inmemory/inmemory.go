@@ -1,56 +1,148 @@
 package inmemory
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 	"todo/model"
 	"todo/storage"
+
+	uuid "github.com/satori/go.uuid"
 )
 
 // InMemory represents in memory structure.
+// Storage passed to WithTx callback shares data with its parent.
 type InMemory struct {
-	todoItems map[string]model.TodoItem
-	users     map[string]model.User
+	*data
+	tx   bool     // true for storage passed to WithTx callback
+	undo *undoLog // records changed in transaction, set with tx
+}
+
+type data struct {
+	mu            sync.RWMutex // held for writing during transactions
+	todoItems     map[string]model.TodoItem
+	users         map[string]model.User
+	activities    []model.Activity
+	notifications map[string]model.Notification
+	boards        map[string]model.Board
+	views         map[string]model.View
+	events        map[string]model.Event
+	eventSeq      int64                     // seq of last added event
+	index         map[string]map[string]int // word -> todo id -> occurrences
+	journal       Journal                   // nil if changes are not persisted
 }
 
 // NewInMemoryStorage returns InMemory struct.
 func NewInMemoryStorage() *InMemory {
-	return &InMemory{map[string]model.TodoItem{}, map[string]model.User{}}
+	return &InMemory{data: &data{
+		todoItems:     map[string]model.TodoItem{},
+		users:         map[string]model.User{},
+		notifications: map[string]model.Notification{},
+		boards:        map[string]model.Board{},
+		views:         map[string]model.View{},
+		events:        map[string]model.Event{},
+		index:         map[string]map[string]int{},
+	}}
+}
+
+// lock locks data for writing and returns unlock func.
+// Storage passed to WithTx callback already holds the lock.
+func (i *InMemory) lock() func() {
+	if i.tx {
+		return func() {}
+	}
+	i.mu.Lock()
+	return i.mu.Unlock
+}
+
+// rlock locks data for reading and returns unlock func.
+func (i *InMemory) rlock() func() {
+	if i.tx {
+		return func() {}
+	}
+	i.mu.RLock()
+	return i.mu.RUnlock
 }
 
 // GetItem gets item from memory.
-func (i *InMemory) GetItem(id string) (model.TodoItem, error) {
-	todo := i.todoItems[id]
+func (i *InMemory) GetItem(ctx context.Context, id string) (model.TodoItem, error) {
+	defer i.rlock()()
+	todo, ok := i.todoItems[id]
+	if !ok {
+		return model.TodoItem{}, nil
+	}
 	location, err := time.LoadLocation(i.users[todo.UserID].Location.String())
 	if err != nil {
 		return model.TodoItem{}, fmt.Errorf("cant load location")
 	}
-	todo.Date = todo.Date.In(location)
+	todo.Date = todo.DateIn(location)
 	return todo, nil
 }
 
-// UpdateItem updates todo in memory.
-func (i *InMemory) UpdateItem(item model.TodoItem) error {
+// UpdateItem updates todo in memory and increments its version, missing todo is not created.
+func (i *InMemory) UpdateItem(ctx context.Context, item model.TodoItem) error {
+	defer i.lock()()
+	old, ok := i.todoItems[item.ID]
+	if !ok {
+		return nil
+	}
+	item.Version = old.Version + 1
+	item.Created = old.Created
+	item.Completed = completion(old, item)
 	if item.Date.IsZero() {
 		item.Date = time.Now().UTC()
 	} else {
 		item.Date = item.Date.UTC()
 	}
+	if err := i.write(KindTodo, item.ID, item); err != nil {
+		return err
+	}
+	i.indexItem(item)
 	i.todoItems[item.ID] = item
 	return nil
 }
 
+// UpdateItemPositions sets positions of todos in memory, missing todos are skipped.
+func (i *InMemory) UpdateItemPositions(ctx context.Context, items []model.TodoItem) error {
+	defer i.lock()()
+	changed := make([]model.TodoItem, 0, len(items))
+	for _, item := range items {
+		todo, ok := i.todoItems[item.ID]
+		if !ok {
+			continue
+		}
+		todo.Position = item.Position
+		if err := i.write(KindTodo, todo.ID, todo); err != nil {
+			return err
+		}
+		changed = append(changed, todo)
+	}
+	for _, todo := range changed {
+		i.todoItems[todo.ID] = todo
+	}
+	return nil
+}
+
 // DeleteItem deletes todo from memory.
-func (i *InMemory) DeleteItem(id string) error {
+func (i *InMemory) DeleteItem(ctx context.Context, id string) error {
+	defer i.lock()()
+	if err := i.write(KindTodo, id, nil); err != nil {
+		return err
+	}
+	i.unindexItem(id)
 	delete(i.todoItems, id)
 	return nil
 }
 
 // AddItem adds todo to memory.
-func (i *InMemory) AddItem(item model.TodoItem) (string, error) {
+func (i *InMemory) AddItem(ctx context.Context, item model.TodoItem) (string, error) {
+	defer i.lock()()
 	u := uuid.NewV4().String()
 	item.ID = u
+	item.Version = 1
 	if item.Status == "" {
 		item.Status = "new"
 	}
@@ -60,33 +152,180 @@ func (i *InMemory) AddItem(item model.TodoItem) (string, error) {
 	} else {
 		item.Date = item.Date.UTC()
 	}
+	if item.Created.IsZero() {
+		item.Created = time.Now().UTC()
+	} else {
+		item.Created = item.Created.UTC()
+	}
+	item.Completed = completion(model.TodoItem{}, item)
 
+	if err := i.write(KindTodo, u, item); err != nil {
+		return "", err
+	}
+	i.indexItem(item)
 	i.todoItems[u] = item
 	return u, nil
 }
 
+// AddItems adds todos to memory in one transaction.
+func (i *InMemory) AddItems(ctx context.Context, items []model.TodoItem) ([]storage.ItemResult, error) {
+	results := make([]storage.ItemResult, 0, len(items))
+	err := i.WithTx(ctx, func(tx storage.Storage) error {
+		for _, item := range items {
+			id, err := tx.AddItem(ctx, item)
+			if err != nil {
+				return err
+			}
+			results = append(results, storage.ItemResult{ID: id, Version: 1})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// UpdateItems updates todos in memory in one transaction.
+func (i *InMemory) UpdateItems(ctx context.Context, items []model.TodoItem) ([]storage.ItemResult, error) {
+	results := make([]storage.ItemResult, 0, len(items))
+	err := i.WithTx(ctx, func(tx storage.Storage) error {
+		for _, item := range items {
+			old, ok := i.todoItems[item.ID]
+			if !ok {
+				results = append(results, storage.ItemResult{ID: item.ID, Missing: true})
+				continue
+			}
+			if err := tx.UpdateItem(ctx, item); err != nil {
+				return err
+			}
+			results = append(results, storage.ItemResult{ID: item.ID, Version: old.Version + 1})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// DeleteItems deletes todos from memory in one transaction.
+func (i *InMemory) DeleteItems(ctx context.Context, ids []string) ([]storage.ItemResult, error) {
+	results := make([]storage.ItemResult, 0, len(ids))
+	err := i.WithTx(ctx, func(tx storage.Storage) error {
+		for _, id := range ids {
+			if _, ok := i.todoItems[id]; !ok {
+				results = append(results, storage.ItemResult{ID: id, Missing: true})
+				continue
+			}
+			if err := tx.DeleteItem(ctx, id); err != nil {
+				return err
+			}
+			results = append(results, storage.ItemResult{ID: id})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// completion returns completion time of todo changed from old.
+// It is kept while todo stays done and cleared once it is reopened.
+func completion(old, item model.TodoItem) time.Time {
+	switch {
+	case item.Status != model.StatusDone:
+		return time.Time{}
+	case old.Status == model.StatusDone && !old.Completed.IsZero():
+		return old.Completed
+	case !item.Completed.IsZero():
+		return item.Completed.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// ReassignItems moves todos of one user to another in memory.
+func (i *InMemory) ReassignItems(ctx context.Context, fromUserID, toUserID string) error {
+	defer i.lock()()
+	for id, todo := range i.todoItems {
+		if todo.UserID != fromUserID {
+			continue
+		}
+		todo.UserID = toUserID
+		todo.Version++
+		if err := i.write(KindTodo, id, todo); err != nil {
+			return err
+		}
+		i.todoItems[id] = todo
+	}
+	return nil
+}
+
 // GetAllItems gets all todos from memory.
-func (i *InMemory) GetAllItems(filter storage.TodoFilter) ([]model.TodoItem, error) {
+func (i *InMemory) GetAllItems(ctx context.Context, filter storage.TodoFilter) ([]model.TodoItem, error) {
+	defer i.rlock()()
 	arr := make([]model.TodoItem, 0)
+	// location is loaded and query compiled once per location of todo owners
+	type compiled struct {
+		location *time.Location
+		match    func(model.TodoItem) bool
+	}
+	locations := map[string]compiled{}
 	for _, value := range i.todoItems {
-		location, err := time.LoadLocation(i.users[value.UserID].Location.String())
-		if err != nil {
-			return arr, fmt.Errorf("cant load location")
+		name := i.users[value.UserID].Location.String()
+		c, ok := locations[name]
+		if !ok {
+			location, err := time.LoadLocation(name)
+			if err != nil {
+				return arr, fmt.Errorf("cant load location")
+			}
+			c = compiled{location: location}
+			if filter.Query != nil {
+				c.match = compileQuery(filter.Query, location)
+			}
+			locations[name] = c
 		}
-		value.Date = value.Date.In(location)
-		if filter == (storage.TodoFilter{}) {
+		value.Date = value.DateIn(c.location)
+		if itemFiltered(filter, value) && (c.match == nil || c.match(value)) {
 			arr = append(arr, value)
-		} else {
-			if itemFiltered(filter, value) {
-				arr = append(arr, value)
-			}
+		}
+	}
+
+	storage.SortItems(arr, filter.SortFields())
+
+	if filter.Limit > 0 && len(arr) > filter.Limit {
+		arr = arr[:filter.Limit]
+	}
+	if fields := filter.SelectFields(); fields != nil {
+		for k := range arr {
+			arr[k] = projectItem(arr[k], fields)
 		}
 	}
 	return arr, nil
 }
 
 func itemFiltered(filter storage.TodoFilter, t model.TodoItem) bool {
-	return useridOk(filter.UserID, t.UserID) && statusOk(filter.Status, t.Status) && toDateOk(filter.ToDate, t.Date) && fromDateOk(filter.FromDate, t.Date)
+	return useridOk(filter.UserID, t.UserID) && idOk(filter.IDs, t.ID) && statusOk(filter.Status, t.Status) && toDateOk(filter.ToDate, t.Date) && fromDateOk(filter.FromDate, t.Date) && afterOk(filter.After, filter.SortFields(), t)
+}
+
+func afterOk(after *model.TodoItem, fields []storage.SortField, t model.TodoItem) bool {
+	if after != nil && !storage.ItemBefore(*after, t, fields) {
+		return false
+	}
+	return true
+}
+
+func idOk(ids []string, id string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
 }
 
 func useridOk(userid string, s string) bool {
@@ -118,53 +357,163 @@ func fromDateOk(fromDate *time.Time, d time.Time) bool {
 }
 
 // GetUser gets user from memory.
-func (i *InMemory) GetUser(id string) (model.User, error) {
+func (i *InMemory) GetUser(ctx context.Context, id string) (model.User, error) {
+	defer i.rlock()()
 	user := i.users[id]
 	return user, nil
 }
 
-// UpdateUser updates user in memory.
-func (i *InMemory) UpdateUser(u model.User) error {
+// UpdateUser updates user in memory, missing user is not created.
+func (i *InMemory) UpdateUser(ctx context.Context, u model.User) error {
+	defer i.lock()()
+	if _, ok := i.users[u.ID]; !ok {
+		return nil
+	}
+	if err := i.checkUserName(u); err != nil {
+		return err
+	}
+	if err := i.write(KindUser, u.ID, u); err != nil {
+		return err
+	}
 	i.users[u.ID] = u
 	return nil
 }
 
-// DeleteUser deletes user from memory.
-func (i *InMemory) DeleteUser(id string) error {
+// LockUsers does nothing, transactions in memory never interleave.
+func (i *InMemory) LockUsers(ctx context.Context, ids ...string) error {
+	return nil
+}
+
+// DeleteUser deletes user from memory together with its todos, activities, notifications, board and views.
+func (i *InMemory) DeleteUser(ctx context.Context, id string) error {
+	defer i.lock()()
+	type record struct{ kind, id string }
+	records := []record{{KindUser, id}, {KindBoard, id}}
+	for todoID, todo := range i.todoItems {
+		if todo.UserID == id {
+			records = append(records, record{KindTodo, todoID})
+		}
+	}
+	for _, a := range i.activities {
+		if a.UserID == id {
+			records = append(records, record{KindActivity, a.ID})
+		}
+	}
+	for nID, n := range i.notifications {
+		if n.UserID == id {
+			records = append(records, record{KindNotification, nID})
+		}
+	}
+	for viewID, view := range i.views {
+		if view.UserID == id {
+			records = append(records, record{KindView, viewID})
+		}
+	}
+	for _, r := range records {
+		if err := i.write(r.kind, r.id, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range records {
+		switch r.kind {
+		case KindTodo:
+			i.unindexItem(r.id)
+			delete(i.todoItems, r.id)
+		case KindNotification:
+			delete(i.notifications, r.id)
+		case KindView:
+			delete(i.views, r.id)
+		}
+	}
+	activities := make([]model.Activity, 0, len(i.activities))
+	for _, a := range i.activities {
+		if a.UserID != id {
+			activities = append(activities, a)
+		}
+	}
+	i.activities = activities
+	delete(i.boards, id)
 	delete(i.users, id)
 	return nil
 }
 
 // AddUser adds user to memory.
-func (i *InMemory) AddUser(user model.User) (string, error) {
+func (i *InMemory) AddUser(ctx context.Context, user model.User) (string, error) {
+	defer i.lock()()
+	if err := i.checkUserName(user); err != nil {
+		return "", err
+	}
 	u := uuid.NewV4().String()
 	user.ID = u
+	if err := i.write(KindUser, u, user); err != nil {
+		return "", err
+	}
 	i.users[u] = user
 	return u, nil
 }
 
+// checkUserName fails if username of user is taken by another user, ignoring case.
+func (i *InMemory) checkUserName(user model.User) error {
+	for id, u := range i.users {
+		if id != user.ID && sameUserName(u.UserName, user.UserName) {
+			return fmt.Errorf("username %q already exists: %w", user.UserName, model.ErrConflict)
+		}
+	}
+	return nil
+}
+
 // GetAllUsers gets all users from memory.
-func (i *InMemory) GetAllUsers(filter storage.UserFilter) ([]model.User, error) {
+func (i *InMemory) GetAllUsers(ctx context.Context, filter storage.UserFilter) ([]model.User, error) {
+	defer i.rlock()()
 	arr := make([]model.User, 0)
 	for _, value := range i.users {
-		if filter == (storage.UserFilter{}) {
+		if userFiltered(filter, value) {
 			arr = append(arr, value)
-		} else {
-			if userFiltered(filter, value) {
-				arr = append(arr, value)
-			}
+		}
+	}
+
+	sort.Slice(arr, func(a, b int) bool {
+		return arr[a].ID < arr[b].ID
+	})
+
+	if filter.Limit > 0 && len(arr) > filter.Limit {
+		arr = arr[:filter.Limit]
+	}
+	if fields := filter.SelectFields(); fields != nil {
+		for k := range arr {
+			arr[k] = projectUser(arr[k], fields)
 		}
 	}
 	return arr, nil
 }
 
 func userFiltered(filter storage.UserFilter, t model.User) bool {
-	return usernameOk(filter.UserName, t.UserName)
+	return idOk(filter.IDs, t.ID) && usernameOk(filter.UserName, t.UserName) && userQueryOk(filter.Query, t) && (filter.AfterID == "" || t.ID > filter.AfterID)
+}
+
+func userQueryOk(q string, t model.User) bool {
+	if q == "" {
+		return true
+	}
+	q = strings.ToLower(q)
+	for _, s := range []string{t.UserName, t.FirstName, t.LastName} {
+		if strings.HasPrefix(strings.ToLower(s), q) {
+			return true
+		}
+	}
+	return false
 }
 
 func usernameOk(username string, s string) bool {
-	if username != "" && strings.Compare(username, s) != 0 {
+	if username != "" && !sameUserName(username, s) {
 		return false
 	}
 	return true
 }
+
+// sameUserName compares lower case usernames like lower(username) index in postgres.
+// strings.EqualFold would also match case foldings lower() doesn't apply, like ſ and s.
+func sameUserName(a, b string) bool {
+	return strings.ToLower(a) == strings.ToLower(b)
+}
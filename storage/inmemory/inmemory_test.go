@@ -1,24 +1,33 @@
 package inmemory
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 	"todo/model"
 	"todo/storage"
+	"todo/storage/query"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStorage(t *testing.T) {
+	ctx := context.Background()
 	l, _ := time.LoadLocation("America/New_York")
 	location := model.CustomLocation{Location: l}
-	storageInMemory := InMemory{
-		map[string]model.TodoItem{
+	storageInMemory := InMemory{data: &data{
+		todoItems: map[string]model.TodoItem{
 			"6ba7b810-9dad-11d1-80b4-00c04fd430c8": {
 				ID:   "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
 				Name: "todo1",
 			},
 		},
-		map[string]model.User{
+		users: map[string]model.User{
 			"6ba7b810-9dad-11d1-80b4-00c04fd430c8": {
 				ID:        "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
 				UserName:  "RoxyProxy",
@@ -28,11 +37,11 @@ func TestStorage(t *testing.T) {
 				Location:  location,
 			},
 		},
-	}
+	}}
 
 	t.Run("Get todo item", func(t *testing.T) {
 		want := "todo1"
-		todo, err := storageInMemory.GetItem("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		todo, err := storageInMemory.GetItem(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 		got := todo.Name
 
 		assert.NoError(t, err)
@@ -40,11 +49,11 @@ func TestStorage(t *testing.T) {
 	})
 
 	t.Run("Update todo item", func(t *testing.T) {
-		err := storageInMemory.UpdateItem(model.TodoItem{ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Name: "todo2"})
+		err := storageInMemory.UpdateItem(ctx, model.TodoItem{ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Name: "todo2"})
 		assert.NoError(t, err)
 
 		want := "todo2"
-		todo, err := storageInMemory.GetItem("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		todo, err := storageInMemory.GetItem(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 		got := todo.Name
 
 		assert.NoError(t, err)
@@ -52,10 +61,10 @@ func TestStorage(t *testing.T) {
 	})
 
 	t.Run("Delete todo item", func(t *testing.T) {
-		err := storageInMemory.DeleteItem("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		err := storageInMemory.DeleteItem(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 		assert.NoError(t, err)
 		want := model.TodoItem{}
-		got, err := storageInMemory.GetItem("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		got, err := storageInMemory.GetItem(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
 		assert.NoError(t, err)
 		assertEqual(t, got, want)
@@ -63,22 +72,22 @@ func TestStorage(t *testing.T) {
 
 	t.Run("Add todo item", func(t *testing.T) {
 		todo := model.TodoItem{Name: "todo3"}
-		got, err := storageInMemory.AddItem(todo)
+		got, err := storageInMemory.AddItem(ctx, todo)
 		assert.NoError(t, err)
 		want, err := uuid.FromString(got)
 		if err != nil {
 			t.Errorf("got %+v, want %+v", got, want)
 		}
 
-		err = storageInMemory.DeleteItem(got)
+		err = storageInMemory.DeleteItem(ctx, got)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Get all todo items", func(t *testing.T) {
-		todo1, _ := storageInMemory.AddItem(model.TodoItem{Name: "todo1"})
-		todo2, _ := storageInMemory.AddItem(model.TodoItem{Name: "todo2"})
+		todo1, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo1"})
+		todo2, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo2"})
 
-		todoitems, err := storageInMemory.GetAllItems(storage.TodoFilter{})
+		todoitems, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{})
 		if err != nil {
 			t.Errorf("Error in GetAllItems %q", err)
 		}
@@ -88,9 +97,9 @@ func TestStorage(t *testing.T) {
 
 		assertEqual(t, got, want)
 
-		err = storageInMemory.DeleteItem(todo1)
+		err = storageInMemory.DeleteItem(ctx, todo1)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteItem(todo2)
+		err = storageInMemory.DeleteItem(ctx, todo2)
 		assert.NoError(t, err)
 	})
 
@@ -98,14 +107,14 @@ func TestStorage(t *testing.T) {
 		date1 := time.Now()
 		date2 := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
 		date3 := time.Date(2029, 11, 17, 20, 34, 58, 651387237, time.UTC)
-		todo1, _ := storageInMemory.AddItem(model.TodoItem{Name: "todo1", Date: date1, Status: "New"})
-		todo2, _ := storageInMemory.AddItem(model.TodoItem{Name: "todo2", Date: date2, Status: "New"})
-		todo3, _ := storageInMemory.AddItem(model.TodoItem{Name: "todo2", Date: date3, Status: "New"})
+		todo1, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo1", Date: date1, Status: "New"})
+		todo2, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo2", Date: date2, Status: "New"})
+		todo3, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo2", Date: date3, Status: "New"})
 
 		date4 := time.Date(2000, 11, 17, 20, 34, 58, 651387237, time.UTC)
 
 		filter := storage.TodoFilter{ToDate: &date1, Status: "New", FromDate: &date4}
-		todoitems, err := storageInMemory.GetAllItems(filter)
+		todoitems, err := storageInMemory.GetAllItems(ctx, filter)
 		if err != nil {
 			t.Errorf("Error in GetAllItems %q", err)
 		}
@@ -114,12 +123,91 @@ func TestStorage(t *testing.T) {
 		want := 2
 
 		assertEqual(t, got, want)
-		err = storageInMemory.DeleteItem(todo1)
+		err = storageInMemory.DeleteItem(ctx, todo1)
+		assert.NoError(t, err)
+		err = storageInMemory.DeleteItem(ctx, todo2)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteItem(todo2)
+		err = storageInMemory.DeleteItem(ctx, todo3)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Get todo items page", func(t *testing.T) {
+		date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+		todo1, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo1", Date: date.Add(time.Hour)})
+		todo2, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo2", Date: date})
+		todo3, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo3", Date: date})
+
+		first, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, first, 2)
+
+		rest, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{After: &first[len(first)-1], Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, rest, 1)
+		assert.Equal(t, todo1, rest[0].ID)
+
+		got := []string{first[0].Name, first[1].Name}
+		assert.ElementsMatch(t, []string{"todo2", "todo3"}, got)
+
+		for _, id := range []string{todo1, todo2, todo3} {
+			assert.NoError(t, storageInMemory.DeleteItem(ctx, id))
+		}
+	})
+
+	t.Run("Get sorted todo items page", func(t *testing.T) {
+		date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+		ids := []string{}
+		for _, todo := range []model.TodoItem{
+			{Name: "b", Date: date, Status: "new"},
+			{Name: "a", Date: date, Status: "done"},
+			{Name: "c", Date: date, Status: "new"},
+			{Name: "a", Date: date.Add(time.Hour), Status: "new"},
+		} {
+			id, _ := storageInMemory.AddItem(ctx, todo)
+			ids = append(ids, id)
+		}
+
+		sort := []storage.SortField{{Field: "status", Desc: true}, {Field: "name"}}
+		var got []string
+		filter := storage.TodoFilter{Sort: sort, Limit: 3}
+		for {
+			page, err := storageInMemory.GetAllItems(ctx, filter)
+			assert.NoError(t, err)
+			for _, todo := range page {
+				got = append(got, todo.Status+":"+todo.Name)
+			}
+			if len(page) < filter.Limit {
+				break
+			}
+			filter.After = &page[len(page)-1]
+		}
+		assert.Equal(t, []string{"new:a", "new:b", "new:c", "done:a"}, got)
+
+		for _, id := range ids {
+			assert.NoError(t, storageInMemory.DeleteItem(ctx, id))
+		}
+	})
+
+	t.Run("Get todo items with fields", func(t *testing.T) {
+		id, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "todo1", Status: "new", Position: 3})
+
+		todos, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{Fields: []string{"name"}})
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteItem(todo3)
+		assert.Equal(t, []model.TodoItem{{ID: id, Name: "todo1", Date: todos[0].Date}}, todos)
+		assert.False(t, todos[0].Date.IsZero(), "date is kept for default sort")
+
+		assert.NoError(t, storageInMemory.DeleteItem(ctx, id))
+	})
+
+	t.Run("Get all-day todo item", func(t *testing.T) {
+		date := time.Date(2021, 11, 8, 0, 0, 0, 0, time.UTC)
+		id, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "holiday", Date: date, AllDay: true, UserID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"})
+
+		todo, err := storageInMemory.GetItem(ctx, id)
 		assert.NoError(t, err)
+		assert.Equal(t, date, todo.Date, "all-day date is not moved to users location")
+
+		assert.NoError(t, storageInMemory.DeleteItem(ctx, id))
 	})
 
 	t.Run("Get user", func(t *testing.T) {
@@ -133,54 +221,54 @@ func TestStorage(t *testing.T) {
 			Password:  "$2a$14$Vv0FoIWcwWSf0mXMy.jFXebqBj/KXBetgN725ComfazcNemFUMVli",
 			Location:  location,
 		}
-		user, err := storageInMemory.GetUser("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		user, err := storageInMemory.GetUser(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 		if err != nil {
 			t.Errorf("Error in GetUser %q", err)
 		}
 		assert.Equal(t, want, user)
 
-		err = storageInMemory.DeleteUser("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		err = storageInMemory.DeleteUser(ctx, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 		assert.NoError(t, err)
 	})
 	t.Run("Add user", func(t *testing.T) {
 		newUser := model.User{UserName: "Roxy1", Password: "Proxy1"}
-		id, err := storageInMemory.AddUser(newUser)
+		id, err := storageInMemory.AddUser(ctx, newUser)
 		if err != nil {
 			t.Errorf("Error in AddUser %q", err)
 		}
 
-		user, _ := storageInMemory.GetUser(id)
+		user, _ := storageInMemory.GetUser(ctx, id)
 		newUser.ID = id
 		assert.Equal(t, newUser, user)
 
-		err = storageInMemory.DeleteUser(id)
+		err = storageInMemory.DeleteUser(ctx, id)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Delete user", func(t *testing.T) {
 		newUser := model.User{UserName: "Roxy2", Password: "Proxy2"}
-		id, _ := storageInMemory.AddUser(newUser)
-		err := storageInMemory.DeleteUser(id)
+		id, _ := storageInMemory.AddUser(ctx, newUser)
+		err := storageInMemory.DeleteUser(ctx, id)
 		if err != nil {
 			t.Errorf("Error in DeleteUser %q", err)
 		}
-		user, _ := storageInMemory.GetUser(id)
+		user, _ := storageInMemory.GetUser(ctx, id)
 		assert.Equal(t, user, model.User{})
 	})
 
 	t.Run("Update user", func(t *testing.T) {
 		newUser := model.User{UserName: "Roxy2", Password: "Proxy2"}
-		id, _ := storageInMemory.AddUser(newUser)
+		id, _ := storageInMemory.AddUser(ctx, newUser)
 		newUser.ID = id
 		newUser.UserName = "Roxy3"
-		err := storageInMemory.UpdateUser(newUser)
+		err := storageInMemory.UpdateUser(ctx, newUser)
 		if err != nil {
 			t.Errorf("Error in UpdateUser %q", err)
 		}
-		user, _ := storageInMemory.GetUser(id)
+		user, _ := storageInMemory.GetUser(ctx, id)
 		assert.Equal(t, newUser.UserName, user.UserName)
 
-		err = storageInMemory.DeleteUser(id)
+		err = storageInMemory.DeleteUser(ctx, id)
 		assert.NoError(t, err)
 	})
 
@@ -188,49 +276,501 @@ func TestStorage(t *testing.T) {
 		user1 := model.User{UserName: "Roxy1", Password: "Proxy1"}
 		user2 := model.User{UserName: "Roxy2", Password: "Proxy2"}
 		user3 := model.User{UserName: "Roxy3", Password: "Proxy3"}
-		id1, _ := storageInMemory.AddUser(user1)
-		id2, _ := storageInMemory.AddUser(user2)
-		id3, _ := storageInMemory.AddUser(user3)
+		id1, _ := storageInMemory.AddUser(ctx, user1)
+		id2, _ := storageInMemory.AddUser(ctx, user2)
+		id3, _ := storageInMemory.AddUser(ctx, user3)
 
-		users, err := storageInMemory.GetAllUsers(storage.UserFilter{})
+		users, err := storageInMemory.GetAllUsers(ctx, storage.UserFilter{})
 		if err != nil {
 			t.Errorf("Error in GetAllUsers %q", err)
 		}
 
 		assert.Equal(t, 3, len(users))
 
-		err = storageInMemory.DeleteUser(id1)
+		err = storageInMemory.DeleteUser(ctx, id1)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteUser(id2)
+		err = storageInMemory.DeleteUser(ctx, id2)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteUser(id3)
+		err = storageInMemory.DeleteUser(ctx, id3)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Get filtered users", func(t *testing.T) {
 		user1 := model.User{UserName: "Roxy1", Password: "Proxy1"}
 		user2 := model.User{UserName: "Roxy2", Password: "Proxy2"}
-		user3 := model.User{UserName: "Roxy1", Password: "Proxy1"}
-		id1, _ := storageInMemory.AddUser(user1)
-		id2, _ := storageInMemory.AddUser(user2)
-		id3, _ := storageInMemory.AddUser(user3)
+		user3 := model.User{UserName: "roxy1", Password: "Proxy1"}
+		id1, _ := storageInMemory.AddUser(ctx, user1)
+		id2, _ := storageInMemory.AddUser(ctx, user2)
+		_, err := storageInMemory.AddUser(ctx, user3)
+		assert.ErrorIs(t, err, model.ErrConflict, "usernames are unique ignoring case")
 
-		users, err := storageInMemory.GetAllUsers(storage.UserFilter{UserName: "Roxy1"})
+		users, err := storageInMemory.GetAllUsers(ctx, storage.UserFilter{UserName: "ROXY1"})
 		if err != nil {
 			t.Errorf("Error in GetAllUsers %q", err)
 		}
 
-		assert.Equal(t, 2, len(users))
+		assert.Equal(t, 1, len(users))
 
-		err = storageInMemory.DeleteUser(id1)
+		err = storageInMemory.DeleteUser(ctx, id1)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteUser(id2)
+		err = storageInMemory.DeleteUser(ctx, id2)
 		assert.NoError(t, err)
-		err = storageInMemory.DeleteUser(id3)
+	})
+
+	t.Run("Search users by prefix", func(t *testing.T) {
+		id1, _ := storageInMemory.AddUser(ctx, model.User{UserName: "alice", FirstName: "Alice", LastName: "Smith", Password: "secret"})
+		id2, _ := storageInMemory.AddUser(ctx, model.User{UserName: "bob", FirstName: "Robert", LastName: "Alison", Password: "secret"})
+		id3, _ := storageInMemory.AddUser(ctx, model.User{UserName: "carol", FirstName: "Carol", LastName: "Malice", Password: "secret"})
+
+		users, err := storageInMemory.GetAllUsers(ctx, storage.UserFilter{Query: "ALI", Fields: storage.PublicUserFields})
 		assert.NoError(t, err)
+
+		got := []string{}
+		for _, u := range users {
+			assert.Empty(t, u.Password)
+			got = append(got, u.UserName)
+		}
+		assert.ElementsMatch(t, []string{"alice", "bob"}, got)
+
+		for _, id := range []string{id1, id2, id3} {
+			assert.NoError(t, storageInMemory.DeleteUser(ctx, id))
+		}
 	})
 }
 
+func TestActivities(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	date := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	id1, err := storageInMemory.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: "user1", Date: date})
+	assert.NoError(t, err)
+	id2, err := storageInMemory.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCompleted, UserID: "user1", Date: date.Add(time.Hour)})
+	assert.NoError(t, err)
+	_, err = storageInMemory.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: "user2", Date: date})
+	assert.NoError(t, err)
+
+	t.Run("Get all activities", func(t *testing.T) {
+		activities, err := storageInMemory.GetAllActivities(ctx, storage.ActivityFilter{UserID: "user1"})
+		assert.NoError(t, err)
+		assert.Len(t, activities, 2)
+		assert.Equal(t, id2, activities[0].ID)
+		assert.Equal(t, id1, activities[1].ID)
+	})
+
+	t.Run("Get filtered activities", func(t *testing.T) {
+		filter := storage.ActivityFilter{UserID: "user1", Types: []string{model.ActivityTodoCreated}}
+		activities, err := storageInMemory.GetAllActivities(ctx, filter)
+		assert.NoError(t, err)
+		assert.Len(t, activities, 1)
+		assert.Equal(t, id1, activities[0].ID)
+	})
+
+	t.Run("Get activities page", func(t *testing.T) {
+		filter := storage.ActivityFilter{UserID: "user1", Limit: 1}
+		activities, err := storageInMemory.GetAllActivities(ctx, filter)
+		assert.NoError(t, err)
+		assert.Len(t, activities, 1)
+		assert.Equal(t, id2, activities[0].ID)
+
+		filter.Before = &storage.Cursor{Date: activities[0].Date, ID: activities[0].ID}
+		activities, err = storageInMemory.GetAllActivities(ctx, filter)
+		assert.NoError(t, err)
+		assert.Len(t, activities, 1)
+		assert.Equal(t, id1, activities[0].ID)
+	})
+}
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	location, _ := time.LoadLocation("America/New_York")
+	// 2021-10-02 01:00 UTC is still October 1st in New York.
+	date := time.Date(2021, 10, 2, 1, 0, 0, 0, time.UTC)
+
+	_, _ = storageInMemory.AddItem(ctx, model.TodoItem{UserID: "user1", Status: model.StatusDone, Date: date, Created: date, Completed: date.Add(24 * time.Hour)})
+	_, _ = storageInMemory.AddItem(ctx, model.TodoItem{UserID: "user1", Status: model.StatusNew, Date: date, Created: date.Add(time.Hour)})
+	_, _ = storageInMemory.AddItem(ctx, model.TodoItem{UserID: "user1", Status: model.StatusNew, Date: date.AddDate(0, 0, 5), Created: date.AddDate(-1, 0, 0)})
+	// todo saved before creation times were recorded
+	assert.NoError(t, storageInMemory.Restore(model.TodoItem{ID: "legacy", UserID: "user1", Status: model.StatusDone, Date: date, Completed: date}))
+
+	stats, err := storageInMemory.GetStats(ctx, storage.StatsFilter{
+		UserID:   "user1",
+		From:     time.Date(2021, 9, 1, 0, 0, 0, 0, location),
+		To:       time.Date(2021, 11, 1, 0, 0, 0, 0, location),
+		Bucket:   model.BucketDay,
+		Location: location,
+		Now:      date.AddDate(0, 0, 1),
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]int{model.StatusDone: 2, model.StatusNew: 2}, stats.ByStatus)
+	assert.Equal(t, 1, stats.Overdue)
+	assert.Equal(t, 1, stats.Untracked)
+	assert.Equal(t, float64(24*60*60), stats.AvgTimeToComplete)
+	assert.Equal(t, []model.StatsBucket{
+		{Start: time.Date(2021, 10, 1, 0, 0, 0, 0, location), Created: 2, Completed: 1},
+		{Start: time.Date(2021, 10, 2, 0, 0, 0, 0, location), Completed: 1},
+	}, stats.Buckets)
+}
+
+func TestNotifications(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	date := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	id1, err := storageInMemory.AddNotification(ctx, model.Notification{Type: model.NotificationMention, UserID: "user1", Date: date})
+	assert.NoError(t, err)
+	id2, err := storageInMemory.AddNotification(ctx, model.Notification{Type: model.NotificationMention, UserID: "user1", Date: date.Add(time.Hour)})
+	assert.NoError(t, err)
+	_, err = storageInMemory.AddNotification(ctx, model.Notification{Type: model.NotificationMention, UserID: "user2", Date: date})
+	assert.NoError(t, err)
+
+	t.Run("Mark notification read", func(t *testing.T) {
+		err := storageInMemory.MarkNotificationRead(ctx, id1)
+		assert.NoError(t, err)
+
+		unread, err := storageInMemory.CountUnreadNotifications(ctx, "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, unread)
+
+		notifications, err := storageInMemory.GetAllNotifications(ctx, storage.NotificationFilter{UserID: "user1", UnreadOnly: true})
+		assert.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, id2, notifications[0].ID)
+	})
+
+	t.Run("Mark all notifications read", func(t *testing.T) {
+		err := storageInMemory.MarkAllNotificationsRead(ctx, "user1")
+		assert.NoError(t, err)
+
+		unread, err := storageInMemory.CountUnreadNotifications(ctx, "user1")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, unread)
+
+		unread, err = storageInMemory.CountUnreadNotifications(ctx, "user2")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, unread)
+	})
+
+	t.Run("Delete old notifications", func(t *testing.T) {
+		err := storageInMemory.DeleteNotificationsBefore(ctx, date.Add(time.Minute))
+		assert.NoError(t, err)
+
+		notifications, err := storageInMemory.GetAllNotifications(ctx, storage.NotificationFilter{UserID: "user1"})
+		assert.NoError(t, err)
+		assert.Len(t, notifications, 1)
+		assert.Equal(t, id2, notifications[0].ID)
+	})
+}
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+
+	board, err := storageInMemory.GetBoard(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Empty(t, board.Columns)
+
+	columns := []model.BoardColumn{{Status: "new"}, {Status: "doing", WIPLimit: 3}, {Status: "done"}}
+	err = storageInMemory.UpdateBoard(ctx, model.Board{UserID: "user1", Columns: columns})
+	assert.NoError(t, err)
+
+	board, err = storageInMemory.GetBoard(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Equal(t, columns, board.Columns)
+
+	board, err = storageInMemory.GetBoard(ctx, "user2")
+	assert.NoError(t, err)
+	assert.Empty(t, board.Columns)
+}
+
+func TestSearchItems(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	userID, err := storageInMemory.AddUser(ctx, model.User{UserName: "Roxy", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+
+	id1, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Pay invoice for hosting", UserID: userID})
+	assert.NoError(t, err)
+	id2, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Invoice", UserID: userID})
+	assert.NoError(t, err)
+	_, err = storageInMemory.AddItem(ctx, model.TodoItem{Name: "Invoice", UserID: "someone"})
+	assert.NoError(t, err)
+	id4, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Buy milk", UserID: userID})
+	assert.NoError(t, err)
+
+	t.Run("Search ranks matches", func(t *testing.T) {
+		results, err := storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "invoice", UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, id2, results[0].Todo.ID)
+		assert.Equal(t, id1, results[1].Todo.ID)
+		assert.Equal(t, "Pay <b>invoice</b> for hosting", results[1].Snippet)
+	})
+
+	t.Run("Search requires all words", func(t *testing.T) {
+		results, err := storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "invoice hosting", UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, id1, results[0].Todo.ID)
+	})
+
+	t.Run("Search follows updates", func(t *testing.T) {
+		err := storageInMemory.UpdateItem(ctx, model.TodoItem{ID: id4, Name: "Buy milk and send invoice", UserID: userID})
+		assert.NoError(t, err)
+		err = storageInMemory.DeleteItem(ctx, id2)
+		assert.NoError(t, err)
+
+		results, err := storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "INVOICE", UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+
+		results, err = storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "milk", UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, "Buy <b>milk</b> and send invoice", results[0].Snippet)
+	})
+
+	t.Run("Search index skips todo journal refused", func(t *testing.T) {
+		failing := NewJournaledStorage(func(kind, id string, record interface{}) error {
+			return errors.New("disk full")
+		})
+		_, err := failing.AddItem(ctx, model.TodoItem{Name: "Pay invoice", UserID: userID})
+		assert.Error(t, err)
+		assert.Empty(t, failing.index)
+		assert.Empty(t, failing.todoItems)
+	})
+
+	t.Run("Search escapes snippet", func(t *testing.T) {
+		_, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "<script>alert('milk')</script>", UserID: userID})
+		assert.NoError(t, err)
+
+		results, err := storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "alert", UserID: userID})
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "&lt;script&gt;<b>alert</b>(&#39;milk&#39;)&lt;/script&gt;", results[0].Snippet)
+		}
+	})
+}
+
+func TestQueryItems(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	l, _ := time.LoadLocation("America/New_York")
+	userID, err := storageInMemory.AddUser(ctx, model.User{UserName: "Roxy", Location: model.CustomLocation{Location: l}})
+	assert.NoError(t, err)
+
+	id1, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Pay Invoice", Status: "done", UserID: userID,
+		Date: time.Date(2026, 11, 1, 23, 0, 0, 0, l)})
+	id2, _ := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Buy milk", Status: "done", UserID: userID,
+		Date: time.Date(2026, 10, 30, 9, 0, 0, 0, l)})
+	_, _ = storageInMemory.AddItem(ctx, model.TodoItem{Name: "Walk dog", Status: "new", UserID: userID,
+		Date: time.Date(2026, 10, 30, 9, 0, 0, 0, l)})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`status:done AND (name~"invoice" OR due<2026-11-01)`, []string{id1, id2}},
+		{`status:done NOT name~invoice`, []string{id2}},
+		{`due:2026-11-01`, []string{id1}},
+		{`due>2026-10-30 OR name="Buy milk"`, []string{id1, id2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			expr, err := query.Parse(tt.query)
+			assert.NoError(t, err)
+
+			todos, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Query: expr})
+			assert.NoError(t, err)
+
+			var got []string
+			for _, todo := range todos {
+				got = append(got, todo.ID)
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestViews(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+
+	id1, err := storageInMemory.AddView(ctx, model.View{Name: "work", Query: "name~work", UserID: "user1"})
+	assert.NoError(t, err)
+	id2, err := storageInMemory.AddView(ctx, model.View{Name: "done", Status: "done", Sort: "-date", UserID: "user1"})
+	assert.NoError(t, err)
+	_, err = storageInMemory.AddView(ctx, model.View{Name: "other", UserID: "user2"})
+	assert.NoError(t, err)
+
+	views, err := storageInMemory.GetAllViews(ctx, "user1")
+	assert.NoError(t, err)
+	assert.Len(t, views, 2)
+	assert.Equal(t, id2, views[0].ID)
+	assert.Equal(t, id1, views[1].ID)
+
+	err = storageInMemory.UpdateView(ctx, model.View{ID: id1, Name: "work items", UserID: "user1"})
+	assert.NoError(t, err)
+	view, err := storageInMemory.GetView(ctx, id1)
+	assert.NoError(t, err)
+	assert.Equal(t, "work items", view.Name)
+
+	err = storageInMemory.DeleteView(ctx, id1)
+	assert.NoError(t, err)
+	view, err = storageInMemory.GetView(ctx, id1)
+	assert.NoError(t, err)
+	assert.Equal(t, model.View{}, view)
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	userID, err := storageInMemory.AddUser(ctx, model.User{UserName: "Roxy", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+
+	t.Run("Commit", func(t *testing.T) {
+		var id string
+		err := storageInMemory.WithTx(ctx, func(tx storage.Storage) error {
+			var err error
+			id, err = tx.AddItem(ctx, model.TodoItem{Name: "committed", UserID: userID})
+			return err
+		})
+		assert.NoError(t, err)
+
+		todo, err := storageInMemory.GetItem(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "committed", todo.Name)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		failed := errors.New("failed")
+		err := storageInMemory.WithTx(ctx, func(tx storage.Storage) error {
+			if _, err := tx.AddItem(ctx, model.TodoItem{Name: "rolled back", UserID: userID}); err != nil {
+				return err
+			}
+			if _, err := tx.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: userID}); err != nil {
+				return err
+			}
+			// nested transaction joins outer one
+			return tx.WithTx(ctx, func(tx storage.Storage) error {
+				if err := tx.DeleteUser(ctx, userID); err != nil {
+					return err
+				}
+				return failed
+			})
+		})
+		assert.Equal(t, failed, err)
+
+		todos, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+		assert.NoError(t, err)
+		assert.Len(t, todos, 1)
+		assert.Equal(t, "committed", todos[0].Name)
+
+		results, err := storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "rolled", UserID: userID})
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		activities, err := storageInMemory.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+		assert.NoError(t, err)
+		assert.Empty(t, activities)
+
+		user, err := storageInMemory.GetUser(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, user.ID)
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	storageInMemory := NewInMemoryStorage()
+	userID, err := storageInMemory.AddUser(ctx, model.User{UserName: "Roxy", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 50; k++ {
+				id, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "concurrent todo", UserID: userID})
+				assert.NoError(t, err)
+				_, err = storageInMemory.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+				assert.NoError(t, err)
+				err = storageInMemory.WithTx(ctx, func(tx storage.Storage) error {
+					todo, err := tx.GetItem(ctx, id)
+					if err != nil {
+						return err
+					}
+					todo.Status = model.StatusDone
+					return tx.UpdateItem(ctx, todo)
+				})
+				assert.NoError(t, err)
+				_, err = storageInMemory.SearchItems(ctx, storage.TodoSearch{Query: "concurrent", UserID: userID})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	todos, err := storageInMemory.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Status: model.StatusDone})
+	assert.NoError(t, err)
+	assert.Len(t, todos, 400)
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "todo.snapshot")
+	l, _ := time.LoadLocation("America/New_York")
+
+	storageInMemory := NewInMemoryStorage()
+	assert.NoError(t, storageInMemory.LoadSnapshot(path))
+
+	userID, err := storageInMemory.AddUser(ctx, model.User{UserName: "Roxy", Password: "hash", Location: model.CustomLocation{Location: l}})
+	assert.NoError(t, err)
+	todoID, err := storageInMemory.AddItem(ctx, model.TodoItem{Name: "Pay invoice", UserID: userID})
+	assert.NoError(t, err)
+	_, err = storageInMemory.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: userID, TodoID: todoID})
+	assert.NoError(t, err)
+	viewID, err := storageInMemory.AddView(ctx, model.View{Name: "open", Status: "new", UserID: userID})
+	assert.NoError(t, err)
+	assert.NoError(t, storageInMemory.UpdateBoard(ctx, model.Board{UserID: userID, Columns: []model.BoardColumn{{Status: "new", WIPLimit: 2}}}))
+	assert.NoError(t, storageInMemory.SaveSnapshot(path))
+
+	loaded := NewInMemoryStorage()
+	assert.NoError(t, loaded.LoadSnapshot(path))
+
+	user, err := loaded.GetUser(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash", user.Password)
+	assert.Equal(t, l.String(), user.Location.String())
+
+	todo, err := loaded.GetItem(ctx, todoID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pay invoice", todo.Name)
+	assert.Equal(t, userID, todo.UserID)
+
+	results, err := loaded.SearchItems(ctx, storage.TodoSearch{Query: "invoice", UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	activities, err := loaded.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+
+	view, err := loaded.GetView(ctx, viewID)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, view.UserID)
+
+	board, err := loaded.GetBoard(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.BoardColumn{{Status: "new", WIPLimit: 2}}, board.Columns)
+
+	_, err = loaded.AddNotification(ctx, model.Notification{UserID: userID})
+	assert.NoError(t, err)
+}
+
 func assertEqual(t *testing.T, got interface{}, want interface{}) {
 	t.Helper()
 	if !reflect.DeepEqual(got, want) {
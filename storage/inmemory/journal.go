@@ -0,0 +1,68 @@
+package inmemory
+
+import (
+	"fmt"
+	"todo/model"
+)
+
+// Kinds of records passed to journal.
+const (
+	KindTodo         = "todos"
+	KindUser         = "users"
+	KindActivity     = "activities"
+	KindNotification = "notifications"
+	KindBoard        = "boards"
+	KindView         = "views"
+	KindEvent        = "events"
+)
+
+// Journal persists records changed in memory, nil record means deleted record.
+// Change is not applied to memory if journal returns error.
+// Journal is called with write lock held, so calls never overlap.
+type Journal func(kind, id string, record interface{}) error
+
+// NewJournaledStorage returns InMemory struct passing every change to journal.
+func NewJournaledStorage(journal Journal) *InMemory {
+	i := NewInMemoryStorage()
+	i.journal = journal
+	return i
+}
+
+func (i *InMemory) write(kind, id string, record interface{}) error {
+	if i.undo != nil {
+		i.undo.save(i.data, kind, id)
+	}
+	if i.journal == nil {
+		return nil
+	}
+	if err := i.journal(kind, id, record); err != nil {
+		return fmt.Errorf("cant persist %s %s: %v", kind, id, err)
+	}
+	return nil
+}
+
+// Restore puts record read from journal into memory without passing it to journal.
+func (i *InMemory) Restore(record interface{}) error {
+	defer i.lock()()
+	switch r := record.(type) {
+	case model.TodoItem:
+		i.indexItem(r)
+		i.todoItems[r.ID] = r
+	case model.User:
+		i.users[r.ID] = r
+	case model.Activity:
+		i.activities = append(i.activities, r)
+	case model.Notification:
+		i.notifications[r.ID] = r
+	case model.Board:
+		i.boards[r.UserID] = r
+	case model.View:
+		i.views[r.ID] = r
+	case model.Event:
+		i.restoreEventSeq(r)
+		i.events[r.ID] = r
+	default:
+		return fmt.Errorf("unknown record %T", record)
+	}
+	return nil
+}
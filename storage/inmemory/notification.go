@@ -0,0 +1,128 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo/model"
+	"todo/storage"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddNotification adds notification to memory.
+func (i *InMemory) AddNotification(ctx context.Context, n model.Notification) (string, error) {
+	defer i.lock()()
+	n.ID = uuid.NewV4().String()
+	if n.Date.IsZero() {
+		n.Date = time.Now().UTC()
+	} else {
+		n.Date = n.Date.UTC()
+	}
+	if err := i.write(KindNotification, n.ID, n); err != nil {
+		return "", err
+	}
+	i.notifications[n.ID] = n
+	return n.ID, nil
+}
+
+// GetNotification gets notification from memory.
+func (i *InMemory) GetNotification(ctx context.Context, id string) (model.Notification, error) {
+	defer i.rlock()()
+	return i.notifications[id], nil
+}
+
+// GetAllNotifications gets notifications from memory, newest first.
+func (i *InMemory) GetAllNotifications(ctx context.Context, filter storage.NotificationFilter) ([]model.Notification, error) {
+	defer i.rlock()()
+	arr := make([]model.Notification, 0)
+	for _, value := range i.notifications {
+		if notificationFiltered(filter, value) {
+			arr = append(arr, value)
+		}
+	}
+
+	sort.Slice(arr, func(a, b int) bool {
+		return recordBefore(arr[b].Date, arr[b].ID, arr[a].Date, arr[a].ID)
+	})
+
+	if filter.Limit > 0 && len(arr) > filter.Limit {
+		arr = arr[:filter.Limit]
+	}
+	return arr, nil
+}
+
+func notificationFiltered(filter storage.NotificationFilter, n model.Notification) bool {
+	if !useridOk(filter.UserID, n.UserID) {
+		return false
+	}
+	if filter.Type != "" && filter.Type != n.Type {
+		return false
+	}
+	if filter.TodoID != "" && filter.TodoID != n.TodoID {
+		return false
+	}
+	if filter.UnreadOnly && n.Read {
+		return false
+	}
+	if filter.Before != nil && !recordBefore(n.Date, n.ID, filter.Before.Date, filter.Before.ID) {
+		return false
+	}
+	return true
+}
+
+// CountUnreadNotifications counts unread notifications of user.
+func (i *InMemory) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	defer i.rlock()()
+	count := 0
+	for _, value := range i.notifications {
+		if value.UserID == userID && !value.Read {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkNotificationRead marks notification as read.
+func (i *InMemory) MarkNotificationRead(ctx context.Context, id string) error {
+	defer i.lock()()
+	n, ok := i.notifications[id]
+	if !ok {
+		return nil
+	}
+	n.Read = true
+	if err := i.write(KindNotification, id, n); err != nil {
+		return err
+	}
+	i.notifications[id] = n
+	return nil
+}
+
+// MarkAllNotificationsRead marks all notifications of user as read.
+func (i *InMemory) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	defer i.lock()()
+	for id, value := range i.notifications {
+		if value.UserID == userID && !value.Read {
+			value.Read = true
+			if err := i.write(KindNotification, id, value); err != nil {
+				return err
+			}
+			i.notifications[id] = value
+		}
+	}
+	return nil
+}
+
+// DeleteNotificationsBefore deletes notifications older than date.
+func (i *InMemory) DeleteNotificationsBefore(ctx context.Context, date time.Time) error {
+	defer i.lock()()
+	for id, value := range i.notifications {
+		if value.Date.Before(date) {
+			if err := i.write(KindNotification, id, nil); err != nil {
+				return err
+			}
+			delete(i.notifications, id)
+		}
+	}
+	return nil
+}
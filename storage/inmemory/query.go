@@ -0,0 +1,68 @@
+package inmemory
+
+import (
+	"strings"
+	"time"
+	"todo/model"
+	"todo/storage/query"
+)
+
+// compileQuery compiles parsed query to predicate over todos.
+func compileQuery(e query.Expr, location *time.Location) func(model.TodoItem) bool {
+	switch e := e.(type) {
+	case *query.And:
+		left, right := compileQuery(e.Left, location), compileQuery(e.Right, location)
+		return func(t model.TodoItem) bool { return left(t) && right(t) }
+	case *query.Or:
+		left, right := compileQuery(e.Left, location), compileQuery(e.Right, location)
+		return func(t model.TodoItem) bool { return left(t) || right(t) }
+	case *query.Not:
+		inner := compileQuery(e.Expr, location)
+		return func(t model.TodoItem) bool { return !inner(t) }
+	case *query.Comparison:
+		return compileComparison(e, location)
+	default:
+		return func(model.TodoItem) bool { return true }
+	}
+}
+
+func compileComparison(c *query.Comparison, location *time.Location) func(model.TodoItem) bool {
+	switch c.Field {
+	case query.FieldStatus:
+		return func(t model.TodoItem) bool { return compareString(t.Status, c.Op, c.Value) }
+	case query.FieldName:
+		return func(t model.TodoItem) bool { return compareString(t.Name, c.Op, c.Value) }
+	case query.FieldDate:
+		start, end := c.Range(location)
+		return func(t model.TodoItem) bool {
+			switch c.Op {
+			case query.OpEq:
+				return !t.Date.Before(start) && t.Date.Before(end)
+			case query.OpNe:
+				return t.Date.Before(start) || !t.Date.Before(end)
+			case query.OpLt:
+				return t.Date.Before(start)
+			case query.OpLe:
+				return t.Date.Before(end)
+			case query.OpGt:
+				return !t.Date.Before(end)
+			case query.OpGe:
+				return !t.Date.Before(start)
+			}
+			return false
+		}
+	}
+	return func(model.TodoItem) bool { return false }
+}
+
+func compareString(s, op, value string) bool {
+	switch op {
+	case query.OpEq:
+		return s == value
+	case query.OpNe:
+		return s != value
+	case query.OpContains:
+		return strings.Contains(strings.ToLower(s), strings.ToLower(value))
+	}
+	return false
+}
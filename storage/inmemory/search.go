@@ -0,0 +1,131 @@
+package inmemory
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"todo/model"
+	"todo/storage"
+	"unicode"
+)
+
+// SearchItems searches todos in memory using tokenized index.
+// All query words must be present in todo name.
+func (i *InMemory) SearchItems(ctx context.Context, search storage.TodoSearch) ([]model.TodoSearchResult, error) {
+	defer i.rlock()()
+	arr := make([]model.TodoSearchResult, 0)
+	terms := tokenize(search.Query)
+	if len(terms) == 0 {
+		return arr, nil
+	}
+
+	var matched map[string]int
+	for _, term := range terms {
+		ids := i.index[term]
+		next := map[string]int{}
+		for id, count := range ids {
+			if matched == nil {
+				next[id] = count
+			} else if prev, ok := matched[id]; ok {
+				next[id] = prev + count
+			}
+		}
+		matched = next
+	}
+
+	location, err := time.LoadLocation(i.users[search.UserID].Location.String())
+	if err != nil {
+		return arr, fmt.Errorf("cant load location")
+	}
+
+	for id, count := range matched {
+		todo := i.todoItems[id]
+		if todo.UserID != search.UserID {
+			continue
+		}
+		todo.Date = todo.DateIn(location)
+		arr = append(arr, model.TodoSearchResult{
+			Todo:    todo,
+			Rank:    float64(count) / float64(len(tokenize(todo.Name))),
+			Snippet: highlight(todo.Name, terms),
+		})
+	}
+
+	sort.Slice(arr, func(a, b int) bool {
+		if arr[a].Rank != arr[b].Rank {
+			return arr[a].Rank > arr[b].Rank
+		}
+		return arr[a].Todo.ID < arr[b].Todo.ID
+	})
+
+	if search.Limit > 0 && len(arr) > search.Limit {
+		arr = arr[:search.Limit]
+	}
+	return arr, nil
+}
+
+func (i *data) indexItem(item model.TodoItem) {
+	if i.index == nil {
+		i.index = map[string]map[string]int{}
+	}
+	i.unindexItem(item.ID)
+	for _, token := range tokenize(item.Name) {
+		if i.index[token] == nil {
+			i.index[token] = map[string]int{}
+		}
+		i.index[token][item.ID]++
+	}
+}
+
+func (i *data) unindexItem(id string) {
+	old, ok := i.todoItems[id]
+	if !ok {
+		return
+	}
+	for _, token := range tokenize(old.Name) {
+		delete(i.index[token], id)
+		if len(i.index[token]) == 0 {
+			delete(i.index, token)
+		}
+	}
+}
+
+// tokenize splits text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlight HTML-escapes text and wraps words matching terms in <b></b>.
+func highlight(text string, terms []string) string {
+	match := map[string]bool{}
+	for _, term := range terms {
+		match[term] = true
+	}
+
+	var b strings.Builder
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end])) {
+			end++
+		}
+		if end == start {
+			b.WriteString(html.EscapeString(string(runes[start])))
+			start++
+			continue
+		}
+		word := string(runes[start:end])
+		if match[strings.ToLower(word)] {
+			b.WriteString("<b>" + word + "</b>")
+		} else {
+			b.WriteString(word)
+		}
+		start = end
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package inmemory
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"todo/model"
+)
+
+// snapshot is stored form of in memory data.
+// Search index is not stored, it is rebuilt on load.
+type snapshot struct {
+	TodoItems     map[string]model.TodoItem
+	Users         map[string]model.User
+	Activities    []model.Activity
+	Notifications map[string]model.Notification
+	Boards        map[string]model.Board
+	Views         map[string]model.View
+	Events        map[string]model.Event
+}
+
+// SaveSnapshot writes data to file.
+// File is replaced atomically, so crash while saving keeps previous snapshot.
+func (i *InMemory) SaveSnapshot(path string) error {
+	var buf bytes.Buffer
+	unlock := i.rlock()
+	err := gob.NewEncoder(&buf).Encode(snapshot{
+		TodoItems:     i.todoItems,
+		Users:         i.users,
+		Activities:    i.activities,
+		Notifications: i.notifications,
+		Boards:        i.boards,
+		Views:         i.views,
+		Events:        i.events,
+	})
+	unlock()
+	if err != nil {
+		return fmt.Errorf("cant encode snapshot: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cant create snapshot: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cant write snapshot: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cant write snapshot: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cant write snapshot: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cant replace snapshot: %v", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces data with data read from file.
+// Missing file is not an error, storage stays empty then.
+func (i *InMemory) LoadSnapshot(path string) error {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cant read snapshot: %v", err)
+	}
+
+	s := snapshot{
+		TodoItems:     map[string]model.TodoItem{},
+		Users:         map[string]model.User{},
+		Notifications: map[string]model.Notification{},
+		Boards:        map[string]model.Board{},
+		Views:         map[string]model.View{},
+		Events:        map[string]model.Event{},
+	}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+		return fmt.Errorf("cant decode snapshot: %v", err)
+	}
+
+	defer i.lock()()
+	i.todoItems = s.TodoItems
+	i.users = s.Users
+	i.activities = s.Activities
+	i.notifications = s.Notifications
+	i.boards = s.Boards
+	i.views = s.Views
+	i.events = s.Events
+	for _, e := range i.events {
+		i.restoreEventSeq(e)
+	}
+	i.index = map[string]map[string]int{}
+	for _, item := range i.todoItems {
+		i.indexItem(item)
+	}
+	return nil
+}
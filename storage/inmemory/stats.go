@@ -0,0 +1,68 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"time"
+	"todo/model"
+	"todo/storage"
+)
+
+// GetStats computes todo stats of user from memory.
+// Only non-empty buckets are returned.
+func (i *InMemory) GetStats(ctx context.Context, filter storage.StatsFilter) (model.Stats, error) {
+	defer i.rlock()()
+	stats := model.Stats{ByStatus: map[string]int{}}
+
+	buckets := map[int64]*model.StatsBucket{}
+	bucket := func(date time.Time) *model.StatsBucket {
+		start := storage.BucketStart(date, filter.Bucket, filter.Location)
+		b, ok := buckets[start.Unix()]
+		if !ok {
+			b = &model.StatsBucket{Start: start}
+			buckets[start.Unix()] = b
+		}
+		return b
+	}
+	inRange := func(date time.Time) bool {
+		return !date.IsZero() && !date.Before(filter.From) && date.Before(filter.To)
+	}
+
+	var total float64
+	var completed int
+	today := filter.AllDayToday()
+	for _, t := range i.todoItems {
+		if t.UserID != filter.UserID {
+			continue
+		}
+		stats.ByStatus[t.Status]++
+		if t.Status != model.StatusDone && (t.AllDay && t.Date.Before(today) || !t.AllDay && t.Date.Before(filter.Now)) {
+			stats.Overdue++
+		}
+		if t.Created.IsZero() {
+			stats.Untracked++
+		}
+
+		if inRange(t.Created) {
+			bucket(t.Created).Created++
+		}
+		if inRange(t.Completed) {
+			bucket(t.Completed).Completed++
+			if !t.Created.IsZero() {
+				total += t.Completed.Sub(t.Created).Seconds()
+				completed++
+			}
+		}
+	}
+
+	for _, b := range buckets {
+		stats.Buckets = append(stats.Buckets, *b)
+	}
+	sort.Slice(stats.Buckets, func(a, b int) bool {
+		return stats.Buckets[a].Start.Before(stats.Buckets[b].Start)
+	})
+	if completed > 0 {
+		stats.AvgTimeToComplete = total / float64(completed)
+	}
+	return stats, nil
+}
@@ -0,0 +1,132 @@
+package inmemory
+
+import (
+	"context"
+	"todo/model"
+	"todo/storage"
+)
+
+// WithTx runs fn holding write lock, so transactions never interleave with other operations.
+// Records changed by fn are restored from undo log if it returns error.
+// Nested calls join outer transaction.
+func (i *InMemory) WithTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	if i.tx {
+		return fn(i)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	undo := &undoLog{records: map[undoKey]interface{}{}}
+	if err := fn(&InMemory{data: i.data, tx: true, undo: undo}); err != nil {
+		undo.rollback(i.data)
+		return err
+	}
+	return nil
+}
+
+type undoKey struct{ kind, id string }
+
+// undoLog keeps records as they were before transaction first changed them.
+type undoLog struct {
+	records    map[undoKey]interface{} // nil for records that did not exist
+	activities []model.Activity
+	saved      bool // activities is set
+}
+
+// save remembers record of given kind unless it was already changed in transaction.
+// Activities are never changed in place, only appended or filtered into new slice,
+// so keeping slice taken before first change is enough to restore them.
+func (u *undoLog) save(d *data, kind, id string) {
+	if kind == KindActivity {
+		if !u.saved {
+			u.activities, u.saved = d.activities, true
+		}
+		return
+	}
+	key := undoKey{kind, id}
+	if _, ok := u.records[key]; ok {
+		return
+	}
+	u.records[key] = d.record(kind, id)
+}
+
+// rollback puts saved records back into memory.
+func (u *undoLog) rollback(d *data) {
+	for key, record := range u.records {
+		d.put(key.kind, key.id, record)
+	}
+	if u.saved {
+		d.activities = u.activities
+	}
+}
+
+// record returns stored record of given kind or nil if there is none.
+func (d *data) record(kind, id string) interface{} {
+	var (
+		record interface{}
+		ok     bool
+	)
+	switch kind {
+	case KindTodo:
+		record, ok = d.todoItems[id]
+	case KindUser:
+		record, ok = d.users[id]
+	case KindNotification:
+		record, ok = d.notifications[id]
+	case KindBoard:
+		record, ok = d.boards[id]
+	case KindView:
+		record, ok = d.views[id]
+	case KindEvent:
+		record, ok = d.events[id]
+	}
+	if !ok {
+		return nil
+	}
+	return record
+}
+
+// put stores record of given kind, nil record deletes stored one.
+func (d *data) put(kind, id string, record interface{}) {
+	switch kind {
+	case KindTodo:
+		d.unindexItem(id)
+		if record == nil {
+			delete(d.todoItems, id)
+			return
+		}
+		d.indexItem(record.(model.TodoItem))
+		d.todoItems[id] = record.(model.TodoItem)
+	case KindUser:
+		if record == nil {
+			delete(d.users, id)
+			return
+		}
+		d.users[id] = record.(model.User)
+	case KindNotification:
+		if record == nil {
+			delete(d.notifications, id)
+			return
+		}
+		d.notifications[id] = record.(model.Notification)
+	case KindBoard:
+		if record == nil {
+			delete(d.boards, id)
+			return
+		}
+		d.boards[id] = record.(model.Board)
+	case KindView:
+		if record == nil {
+			delete(d.views, id)
+			return
+		}
+		d.views[id] = record.(model.View)
+	case KindEvent:
+		if record == nil {
+			delete(d.events, id)
+			return
+		}
+		d.events[id] = record.(model.Event)
+	}
+}
@@ -0,0 +1,67 @@
+package inmemory
+
+import (
+	"context"
+	"sort"
+	"todo/model"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddView adds view to memory.
+func (i *InMemory) AddView(ctx context.Context, view model.View) (string, error) {
+	defer i.lock()()
+	view.ID = uuid.NewV4().String()
+	if err := i.write(KindView, view.ID, view); err != nil {
+		return "", err
+	}
+	i.views[view.ID] = view
+	return view.ID, nil
+}
+
+// DeleteView deletes view from memory.
+func (i *InMemory) DeleteView(ctx context.Context, id string) error {
+	defer i.lock()()
+	if err := i.write(KindView, id, nil); err != nil {
+		return err
+	}
+	delete(i.views, id)
+	return nil
+}
+
+// UpdateView updates view in memory, missing view is not created.
+func (i *InMemory) UpdateView(ctx context.Context, view model.View) error {
+	defer i.lock()()
+	if _, ok := i.views[view.ID]; !ok {
+		return nil
+	}
+	if err := i.write(KindView, view.ID, view); err != nil {
+		return err
+	}
+	i.views[view.ID] = view
+	return nil
+}
+
+// GetView gets view from memory.
+func (i *InMemory) GetView(ctx context.Context, id string) (model.View, error) {
+	defer i.rlock()()
+	return i.views[id], nil
+}
+
+// GetAllViews gets all views of user from memory ordered by name.
+func (i *InMemory) GetAllViews(ctx context.Context, userID string) ([]model.View, error) {
+	defer i.rlock()()
+	arr := make([]model.View, 0)
+	for _, value := range i.views {
+		if value.UserID == userID {
+			arr = append(arr, value)
+		}
+	}
+	sort.Slice(arr, func(a, b int) bool {
+		if arr[a].Name != arr[b].Name {
+			return arr[a].Name < arr[b].Name
+		}
+		return arr[a].ID < arr[b].ID
+	})
+	return arr, nil
+}
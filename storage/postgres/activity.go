@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+
+	"github.com/huandu/go-sqlbuilder"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddActivity adds activity to db.
+func (i *Postgres) AddActivity(ctx context.Context, activity model.Activity) (string, error) {
+	activity.ID = uuid.NewV4().String()
+	if activity.Date.IsZero() {
+		activity.Date = time.Now().UTC()
+	} else {
+		activity.Date = activity.Date.UTC()
+	}
+
+	_, err := i.db.Exec(ctx,
+		"INSERT INTO activities (id, type, userid, todoid, todoname, date) VALUES ($1, $2, $3, $4, $5, $6)",
+		activity.ID, activity.Type, activity.UserID, activity.TodoID, activity.TodoName, activity.Date)
+	if err != nil {
+		return "", fmt.Errorf("Unable to INSERT: %v", err)
+	}
+	return activity.ID, nil
+}
+
+// GetAllActivities gets activities from db, newest first.
+func (i *Postgres) GetAllActivities(ctx context.Context, filter storage.ActivityFilter) ([]model.Activity, error) {
+	arr := make([]model.Activity, 0)
+	query, args := activitiesQuery(filter)
+
+	rows, err := i.db.Query(ctx, query, args...)
+	if err != nil {
+		return arr, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := model.Activity{}
+		err := rows.Scan(&a.ID, &a.Type, &a.UserID, &a.TodoID, &a.TodoName, &a.Date)
+		if err != nil {
+			return arr, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		arr = append(arr, a)
+	}
+
+	return arr, rows.Err()
+}
+
+// activitiesQuery builds SELECT of activities matching filter.
+func activitiesQuery(filter storage.ActivityFilter) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select("id", "type", "userid", "todoid", "todoname", "date").From("activities")
+	sb.Where(sb.Equal("userid", filter.UserID))
+
+	if len(filter.Types) > 0 {
+		sb.Where("type = ANY(" + sb.Var(filter.Types) + ")")
+	}
+	if filter.Before != nil {
+		sb.Where("(date, id) < (" + sb.Var(filter.Before.Date.UTC()) + "::timestamp, " + sb.Var(filter.Before.ID) + "::uuid)")
+	}
+	sb.OrderBy("date DESC", "id DESC")
+	if filter.Limit > 0 {
+		sb.Limit(filter.Limit)
+	}
+	return sb.Build()
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+
+	"github.com/jackc/pgx/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddItems adds todos to db with single COPY.
+func (i *Postgres) AddItems(ctx context.Context, items []model.TodoItem) ([]storage.ItemResult, error) {
+	results := make([]storage.ItemResult, 0, len(items))
+	rows := make([][]interface{}, 0, len(items))
+	for _, item := range items {
+		item.ID = uuid.NewV4().String()
+		if item.Status == "" {
+			item.Status = "new"
+		}
+		if item.Date.IsZero() {
+			item.Date = time.Now().UTC()
+		} else {
+			item.Date = item.Date.UTC()
+		}
+		created, completed := completionTimes(item)
+		rows = append(rows, []interface{}{item.ID, item.Name, item.Date, item.Status, item.UserID, item.Position, item.AllDay, created, completed})
+		results = append(results, storage.ItemResult{ID: item.ID, Version: 1})
+	}
+
+	_, err := i.db.CopyFrom(ctx, pgx.Identifier{"todos"},
+		[]string{"id", "name", "date", "status", "userid", "position", "allday", "created", "completed"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to COPY: %v", err)
+	}
+	return results, nil
+}
+
+// UpdateItems updates todos in db with single batch of updates run in transaction.
+func (i *Postgres) UpdateItems(ctx context.Context, items []model.TodoItem) ([]storage.ItemResult, error) {
+	if !i.tx {
+		var results []storage.ItemResult
+		err := i.WithTx(ctx, func(tx storage.Storage) (err error) {
+			results, err = tx.UpdateItems(ctx, items)
+			return err
+		})
+		return results, err
+	}
+
+	b := &pgx.Batch{}
+	for _, item := range items {
+		if !validID(item.ID) {
+			continue
+		}
+		if item.Date.IsZero() {
+			item.Date = time.Now().UTC()
+		} else {
+			item.Date = item.Date.UTC()
+		}
+		b.Queue("UPDATE todos SET name=$2, date=$3, status=$4, userid=$5, position=$6, allday=$7, version=version+1, "+completedColumn+" WHERE id = $1 RETURNING version",
+			item.ID, item.Name, item.Date, item.Status, item.UserID, item.Position, item.AllDay, model.StatusDone, time.Now().UTC())
+	}
+
+	br := i.db.SendBatch(ctx, b)
+	defer br.Close()
+
+	results := make([]storage.ItemResult, 0, len(items))
+	for _, item := range items {
+		result := storage.ItemResult{ID: item.ID}
+		if !validID(item.ID) {
+			result.Missing = true
+			results = append(results, result)
+			continue
+		}
+		err := br.QueryRow().Scan(&result.Version)
+		if err == pgx.ErrNoRows {
+			result.Missing = true
+		} else if err != nil {
+			return nil, fmt.Errorf("Unable to update: %v", err)
+		}
+		results = append(results, result)
+	}
+	return results, br.Close()
+}
+
+// DeleteItems deletes todos in db with single statement.
+func (i *Postgres) DeleteItems(ctx context.Context, ids []string) ([]storage.ItemResult, error) {
+	rows, err := i.db.Query(ctx, "DELETE FROM todos WHERE id = ANY($1::uuid[]) RETURNING id", validIDs(ids))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to DELETE: %v", err)
+	}
+	defer rows.Close()
+
+	deleted := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("Unable to DELETE: %v", err)
+		}
+		deleted[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Unable to DELETE: %v", err)
+	}
+
+	results := make([]storage.ItemResult, 0, len(ids))
+	for _, id := range ids {
+		results = append(results, storage.ItemResult{ID: id, Missing: !deleted[id]})
+	}
+	return results, nil
+}
+
+// validID reports whether id can be cast to uuid, so malformed ids
+// are reported missing instead of failing whole statement.
+func validID(id string) bool {
+	_, err := uuid.FromString(id)
+	return err == nil
+}
+
+// validIDs returns ids that can be cast to uuid.
+func validIDs(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if validID(id) {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"todo/model"
+)
+
+// GetBoard gets board columns configuration of user from db.
+func (i *Postgres) GetBoard(ctx context.Context, userID string) (model.Board, error) {
+	board := model.Board{UserID: userID, Columns: []model.BoardColumn{}}
+
+	rows, err := i.db.Query(ctx,
+		"SELECT status, wiplimit FROM board_columns WHERE userid = $1 ORDER BY position", userID)
+	if err != nil {
+		return model.Board{}, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		column := model.BoardColumn{}
+		if err := rows.Scan(&column.Status, &column.WIPLimit); err != nil {
+			return model.Board{}, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, rows.Err()
+}
+
+// UpdateBoard replaces board columns configuration of user in db.
+// Columns are replaced atomically when called in transaction.
+func (i *Postgres) UpdateBoard(ctx context.Context, board model.Board) error {
+	if _, err := i.db.Exec(ctx, "DELETE FROM board_columns WHERE userid = $1", board.UserID); err != nil {
+		return fmt.Errorf("Unable to DELETE: %v", err)
+	}
+	for position, column := range board.Columns {
+		_, err := i.db.Exec(ctx,
+			"INSERT INTO board_columns (userid, position, status, wiplimit) VALUES ($1, $2, $3, $4)",
+			board.UserID, position, column.Status, column.WIPLimit)
+		if err != nil {
+			return fmt.Errorf("Unable to INSERT: %v", err)
+		}
+	}
+	return nil
+}
+
+// LockBoard locks row of board owner, board may have no columns rows to lock.
+// Key is not locked, so todos of owner can still be added meanwhile.
+// Lock is held until the end of transaction, outside of transaction it does nothing.
+func (i *Postgres) LockBoard(ctx context.Context, userID string) error {
+	if !i.tx {
+		return nil
+	}
+	_, err := i.db.Exec(ctx, "SELECT id FROM users WHERE id = $1 FOR NO KEY UPDATE", userID)
+	if err != nil {
+		return fmt.Errorf("Unable to lock board: %v", err)
+	}
+	return nil
+}
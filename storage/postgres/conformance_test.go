@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	conf "todo/config"
+	"todo/storage"
+	"todo/storage/storagetest"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/jackc/tern/migrate"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConformance runs against database from DATABASE_URL, all its data is deleted.
+func TestConformance(t *testing.T) {
+	godotenv.Load("../../.env")
+	config := conf.New()
+	if config.DBUrl == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	dbpool, err := pgxpool.Connect(ctx, config.DBUrl)
+	if err != nil {
+		t.Fatalf("Unable to connect to database: %v", err)
+	}
+	defer dbpool.Close()
+	migrateDatabase(t, dbpool)
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		_, err := dbpool.Exec(ctx, "TRUNCATE users, todos, activities, notifications, board_columns, views, outbox CASCADE")
+		assert.NoError(t, err)
+		return NewPostgresStorage(dbpool)
+	})
+}
+
+func migrateDatabase(t *testing.T, dbpool *pgxpool.Pool) {
+	ctx := context.Background()
+	conn, err := dbpool.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("Unable to acquire a database connection: %v", err)
+	}
+	defer conn.Release()
+
+	migrator, err := migrate.NewMigrator(ctx, conn.Conn(), "schema_version")
+	if err != nil {
+		t.Fatalf("Unable to create a migrator: %v", err)
+	}
+	if err := migrator.LoadMigrations("./migrations"); err != nil {
+		t.Fatalf("Unable to load migrations: %v", err)
+	}
+	if err := migrator.Migrate(ctx); err != nil {
+		t.Fatalf("Unable to migrate: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddEvent adds event to outbox table.
+func (i *Postgres) AddEvent(ctx context.Context, e model.Event) (string, error) {
+	e.ID = uuid.NewV4().String()
+	if e.Date.IsZero() {
+		e.Date = time.Now().UTC()
+	} else {
+		e.Date = e.Date.UTC()
+	}
+
+	_, err := i.db.Exec(ctx,
+		"INSERT INTO outbox (id, type, userid, payload, date) VALUES ($1, $2, $3, $4, $5)",
+		e.ID, e.Type, e.UserID, string(e.Payload), e.Date)
+	if err != nil {
+		return "", fmt.Errorf("Unable to INSERT: %v", err)
+	}
+	return e.ID, nil
+}
+
+// GetEvents gets undelivered events from outbox table in order they were added.
+func (i *Postgres) GetEvents(ctx context.Context, limit int) ([]model.Event, error) {
+	arr := make([]model.Event, 0)
+	query := "SELECT id, seq, type, userid, payload, date FROM outbox ORDER BY seq"
+	args := []interface{}{}
+	if limit > 0 {
+		query += " LIMIT $1"
+		args = append(args, limit)
+	}
+
+	rows, err := i.db.Query(ctx, query, args...)
+	if err != nil {
+		return arr, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e := model.Event{}
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.Seq, &e.Type, &e.UserID, &payload, &e.Date); err != nil {
+			return arr, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		e.Payload = payload
+		arr = append(arr, e)
+	}
+
+	return arr, rows.Err()
+}
+
+// DeleteEvent deletes delivered event from outbox table.
+func (i *Postgres) DeleteEvent(ctx context.Context, id string) error {
+	_, err := i.db.Exec(ctx, "DELETE FROM outbox WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("Unable to DELETE: %v", err)
+	}
+	return nil
+}
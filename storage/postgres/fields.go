@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"strings"
+
+	"todo/model"
+)
+
+// todoColumns returns SELECT list and scan destinations for todo fields.
+// Nil fields select whole todo.
+func todoColumns(fields []string, item *model.TodoItem) (string, []interface{}) {
+	if fields == nil {
+		return "id, name, date, allday, status, position, version, userid",
+			[]interface{}{&item.ID, &item.Name, &item.Date, &item.AllDay, &item.Status, &item.Position, &item.Version, &item.UserID}
+	}
+
+	columns := make([]string, 0, len(fields))
+	dest := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		switch f {
+		case "id":
+			dest = append(dest, &item.ID)
+		case "name":
+			dest = append(dest, &item.Name)
+		case "date":
+			dest = append(dest, &item.Date)
+		case "allday":
+			dest = append(dest, &item.AllDay)
+		case "status":
+			dest = append(dest, &item.Status)
+		case "position":
+			dest = append(dest, &item.Position)
+		case "version":
+			dest = append(dest, &item.Version)
+		default:
+			continue
+		}
+		columns = append(columns, f)
+	}
+	return strings.Join(columns, ", "), dest
+}
+
+// userColumns returns SELECT list and scan destinations for user fields.
+// Nil fields select whole user including password hash.
+func userColumns(fields []string, user *model.User, location *string) (string, []interface{}) {
+	if fields == nil {
+		return "id, username, firstname, lastname, password, location",
+			[]interface{}{&user.ID, &user.UserName, &user.FirstName, &user.LastName, &user.Password, location}
+	}
+
+	columns := make([]string, 0, len(fields))
+	dest := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		switch f {
+		case "id":
+			dest = append(dest, &user.ID)
+		case "username":
+			dest = append(dest, &user.UserName)
+		case "firstname":
+			dest = append(dest, &user.FirstName)
+		case "lastname":
+			dest = append(dest, &user.LastName)
+		case "location":
+			dest = append(dest, location)
+		default:
+			continue
+		}
+		columns = append(columns, f)
+	}
+	return strings.Join(columns, ", "), dest
+}
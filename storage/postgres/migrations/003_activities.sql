@@ -0,0 +1,13 @@
+CREATE TABLE activities(
+    id uuid DEFAULT uuid_generate_v4 (),
+    type VARCHAR(50) NOT NULL,
+    userid uuid NOT NULL,
+    todoid VARCHAR(36) NOT NULL DEFAULT '',
+    todoname VARCHAR(255) NOT NULL DEFAULT '',
+    date TIMESTAMP NOT NULL,
+    FOREIGN KEY(userid)
+        REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX activities_userid_date_idx ON activities (userid, date DESC, id DESC);
@@ -0,0 +1,16 @@
+CREATE TABLE notifications(
+    id uuid DEFAULT uuid_generate_v4 (),
+    type VARCHAR(50) NOT NULL,
+    userid uuid NOT NULL,
+    actorid VARCHAR(36) NOT NULL DEFAULT '',
+    todoid VARCHAR(36) NOT NULL DEFAULT '',
+    message VARCHAR(512) NOT NULL,
+    read BOOLEAN NOT NULL DEFAULT FALSE,
+    date TIMESTAMP NOT NULL,
+    FOREIGN KEY(userid)
+        REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX notifications_userid_date_idx ON notifications (userid, date DESC, id DESC);
+CREATE INDEX notifications_date_idx ON notifications (date);
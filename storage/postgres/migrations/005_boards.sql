@@ -0,0 +1,12 @@
+ALTER TABLE todos ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE board_columns(
+    userid uuid NOT NULL,
+    position INTEGER NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    wiplimit INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY(userid)
+        REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (userid, position),
+    UNIQUE (userid, status)
+);
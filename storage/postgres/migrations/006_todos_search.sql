@@ -0,0 +1,4 @@
+ALTER TABLE todos ADD COLUMN search tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', name)) STORED;
+
+CREATE INDEX todos_search_idx ON todos USING GIN (search);
@@ -0,0 +1,13 @@
+CREATE TABLE views(
+    id uuid DEFAULT uuid_generate_v4 (),
+    userid uuid NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT '',
+    fromdate TIMESTAMP NULL,
+    todate TIMESTAMP NULL,
+    query TEXT NOT NULL DEFAULT '',
+    sort VARCHAR(255) NOT NULL DEFAULT '',
+    FOREIGN KEY(userid)
+        REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN allday BOOLEAN NOT NULL DEFAULT false;
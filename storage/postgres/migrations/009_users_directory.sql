@@ -0,0 +1,3 @@
+CREATE INDEX users_username_prefix_idx ON users (lower(username) text_pattern_ops);
+CREATE INDEX users_firstname_prefix_idx ON users (lower(firstname) text_pattern_ops);
+CREATE INDEX users_lastname_prefix_idx ON users (lower(lastname) text_pattern_ops);
@@ -0,0 +1,3 @@
+ALTER TABLE todos DROP CONSTRAINT todos_userid_fkey;
+ALTER TABLE todos ADD CONSTRAINT todos_userid_fkey
+    FOREIGN KEY (userid) REFERENCES users (id) ON DELETE CASCADE;
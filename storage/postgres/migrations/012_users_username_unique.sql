@@ -0,0 +1,11 @@
+-- Usernames used to be unique only with exact case. Of users whose names differ
+-- only in case, the one with the lowest id keeps its name, others are renamed to
+-- their name followed by "-" and their id, and have to log in with the new name.
+UPDATE users u SET username = left(d.username, 13) || '-' || d.id::text
+FROM (
+    SELECT id, username, row_number() OVER (PARTITION BY lower(username) ORDER BY id) AS n
+    FROM users
+) d
+WHERE u.id = d.id AND d.n > 1;
+
+CREATE UNIQUE INDEX users_username_unique_idx ON users (lower(username));
@@ -0,0 +1,9 @@
+CREATE TABLE outbox(
+    seq BIGSERIAL,
+    id uuid NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL,
+    userid VARCHAR(36) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    date TIMESTAMP NOT NULL,
+    PRIMARY KEY (seq)
+);
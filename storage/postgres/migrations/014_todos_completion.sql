@@ -0,0 +1,15 @@
+ALTER TABLE todos ADD COLUMN created TIMESTAMP;
+ALTER TABLE todos ADD COLUMN completed TIMESTAMP;
+
+UPDATE todos t SET created = a.date
+FROM activities a
+WHERE a.todoid = t.id::text AND a.type = 'todo.created';
+
+UPDATE todos t SET completed = (
+    SELECT max(a.date) FROM activities a
+    WHERE a.todoid = t.id::text AND a.type = 'todo.completed'
+)
+WHERE t.status = 'done';
+
+CREATE INDEX todos_userid_created_idx ON todos (userid, created);
+CREATE INDEX todos_userid_completed_idx ON todos (userid, completed);
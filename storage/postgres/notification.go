@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jackc/pgx/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddNotification adds notification to db.
+func (i *Postgres) AddNotification(ctx context.Context, n model.Notification) (string, error) {
+	n.ID = uuid.NewV4().String()
+	if n.Date.IsZero() {
+		n.Date = time.Now().UTC()
+	} else {
+		n.Date = n.Date.UTC()
+	}
+
+	_, err := i.db.Exec(ctx,
+		"INSERT INTO notifications (id, type, userid, actorid, todoid, message, read, date) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		n.ID, n.Type, n.UserID, n.ActorID, n.TodoID, n.Message, n.Read, n.Date)
+	if err != nil {
+		return "", fmt.Errorf("Unable to INSERT: %v", err)
+	}
+	return n.ID, nil
+}
+
+// GetNotification gets notification from db, id that is not uuid is not found.
+func (i *Postgres) GetNotification(ctx context.Context, id string) (model.Notification, error) {
+	if !validID(id) {
+		return model.Notification{}, nil
+	}
+	n := model.Notification{}
+	err := i.db.QueryRow(ctx,
+		"SELECT id, type, userid, actorid, todoid, message, read, date FROM notifications WHERE id = $1"+i.forUpdate(),
+		id).Scan(&n.ID, &n.Type, &n.UserID, &n.ActorID, &n.TodoID, &n.Message, &n.Read, &n.Date)
+	if err == pgx.ErrNoRows {
+		return model.Notification{}, nil
+	}
+	if err != nil {
+		return model.Notification{}, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	return n, nil
+}
+
+// GetAllNotifications gets notifications from db, newest first.
+func (i *Postgres) GetAllNotifications(ctx context.Context, filter storage.NotificationFilter) ([]model.Notification, error) {
+	arr := make([]model.Notification, 0)
+	query, args := notificationsQuery(filter)
+
+	rows, err := i.db.Query(ctx, query, args...)
+	if err != nil {
+		return arr, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n := model.Notification{}
+		err := rows.Scan(&n.ID, &n.Type, &n.UserID, &n.ActorID, &n.TodoID, &n.Message, &n.Read, &n.Date)
+		if err != nil {
+			return arr, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		arr = append(arr, n)
+	}
+
+	return arr, rows.Err()
+}
+
+// CountUnreadNotifications counts unread notifications of user in db.
+func (i *Postgres) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := i.db.QueryRow(ctx,
+		"SELECT count(*) FROM notifications WHERE userid = $1 AND NOT read", userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	return count, nil
+}
+
+// MarkNotificationRead marks notification as read in db.
+func (i *Postgres) MarkNotificationRead(ctx context.Context, id string) error {
+	if !validID(id) {
+		return nil
+	}
+	_, err := i.db.Exec(ctx, "UPDATE notifications SET read = TRUE WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("Unable to update: %v", err)
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks all notifications of user as read in db.
+func (i *Postgres) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	_, err := i.db.Exec(ctx, "UPDATE notifications SET read = TRUE WHERE userid = $1 AND NOT read", userID)
+	if err != nil {
+		return fmt.Errorf("Unable to update: %v", err)
+	}
+	return nil
+}
+
+// DeleteNotificationsBefore deletes notifications older than date from db.
+func (i *Postgres) DeleteNotificationsBefore(ctx context.Context, date time.Time) error {
+	_, err := i.db.Exec(ctx, "DELETE FROM notifications WHERE date < $1", date.UTC())
+	if err != nil {
+		return fmt.Errorf("Unable to DELETE: %v", err)
+	}
+	return nil
+}
+
+// notificationsQuery builds SELECT of notifications matching filter.
+func notificationsQuery(filter storage.NotificationFilter) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select("id", "type", "userid", "actorid", "todoid", "message", "read", "date").From("notifications")
+	sb.Where(sb.Equal("userid", filter.UserID))
+
+	if len(filter.Type) > 0 {
+		sb.Where(sb.Equal("type", filter.Type))
+	}
+	if len(filter.TodoID) > 0 {
+		sb.Where(sb.Equal("todoid", filter.TodoID))
+	}
+	if filter.UnreadOnly {
+		sb.Where("NOT read")
+	}
+	if filter.Before != nil {
+		sb.Where("(date, id) < (" + sb.Var(filter.Before.Date.UTC()) + "::timestamp, " + sb.Var(filter.Before.ID) + "::uuid)")
+	}
+	sb.OrderBy("date DESC", "id DESC")
+	if filter.Limit > 0 {
+		sb.Limit(filter.Limit)
+	}
+	return sb.Build()
+}
@@ -2,48 +2,64 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"reflect"
+	"strings"
 	"time"
 
 	"todo/model"
 	"todo/storage"
 
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	uuid "github.com/satori/go.uuid"
 )
 
+// uniqueViolation is error code of unique constraint violation.
+const uniqueViolation = "23505"
+
 // Postgres represents postgres object.
 type Postgres struct {
-	pool *pgxpool.Pool
+	db querier
+	tx bool // true for storage passed to WithTx callback
 }
 
 // NewPostgresStorage returns Postgres struct.
 func NewPostgresStorage(p *pgxpool.Pool) *Postgres {
-	return &Postgres{pool: p}
+	return &Postgres{db: p}
 }
 
 // AddUser adds user to db.
-func (i *Postgres) AddUser(user model.User) (string, error) {
+func (i *Postgres) AddUser(ctx context.Context, user model.User) (string, error) {
 	u := uuid.NewV4().String()
 	user.ID = u
 
-	err := i.pool.QueryRow(context.Background(),
+	err := i.db.QueryRow(ctx,
 		"INSERT INTO users (id, username, firstname, lastname, password, location) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
 		user.ID, user.UserName, user.FirstName, user.LastName, user.Password, user.Location.String()).Scan(&user.ID)
 
 	if err != nil {
-		return "", fmt.Errorf("Unable to INSERT: %v", err)
+		return "", conflictError("Unable to INSERT", err)
 	}
 	return u, nil
 }
 
+// conflictError reports unique constraint violations as model.ErrConflict.
+func conflictError(msg string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%s: %v: %w", msg, err, model.ErrConflict)
+	}
+	return fmt.Errorf("%s: %v", msg, err)
+}
+
 // GetUser gets user from db.
-func (i *Postgres) GetUser(id string) (model.User, error) {
+func (i *Postgres) GetUser(ctx context.Context, id string) (model.User, error) {
 	user := model.User{}
 	var l string
-	err := i.pool.QueryRow(context.Background(),
+	err := i.db.QueryRow(ctx,
 		"SELECT id, username, firstname, lastname, password, location FROM users WHERE id = $1",
 		id).Scan(&user.ID, &user.UserName, &user.FirstName, &user.LastName, &user.Password, &l)
 	if err == pgx.ErrNoRows {
@@ -67,12 +83,12 @@ func (i *Postgres) GetUser(id string) (model.User, error) {
 }
 
 // UpdateUser updates user in db.
-func (i *Postgres) UpdateUser(u model.User) error {
-	_, err := i.pool.Exec(context.Background(),
+func (i *Postgres) UpdateUser(ctx context.Context, u model.User) error {
+	_, err := i.db.Exec(ctx,
 		"UPDATE users SET username = $2, firstname = $3, lastname=$4, password=$5, location=$6 WHERE id = $1",
 		u.ID, u.UserName, u.FirstName, u.LastName, u.Password, u.Location.String())
 	if err != nil {
-		return fmt.Errorf("Unable to update: %v", err)
+		return conflictError("Unable to update", err)
 	}
 
 	/*if ct.RowsAffected() == 0 {
@@ -82,9 +98,22 @@ func (i *Postgres) UpdateUser(u model.User) error {
 	return nil
 }
 
-// DeleteUser deletes user in db.
-func (i *Postgres) DeleteUser(id string) error {
-	_, err := i.pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", id)
+// LockUsers locks rows of users in order of id, users that do not exist are skipped.
+// Lock is held until the end of transaction, outside of transaction it does nothing.
+func (i *Postgres) LockUsers(ctx context.Context, ids ...string) error {
+	if !i.tx {
+		return nil
+	}
+	_, err := i.db.Exec(ctx, "SELECT id FROM users WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE", validIDs(ids))
+	if err != nil {
+		return fmt.Errorf("Unable to lock users: %v", err)
+	}
+	return nil
+}
+
+// DeleteUser deletes user in db, its records are deleted by cascading foreign keys.
+func (i *Postgres) DeleteUser(ctx context.Context, id string) error {
+	_, err := i.db.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
 	if err != nil {
 		return fmt.Errorf("Unable to DELETE: %v", err)
 	}
@@ -96,19 +125,15 @@ func (i *Postgres) DeleteUser(id string) error {
 }
 
 // GetAllUsers gets all users from db.
-func (i *Postgres) GetAllUsers(filter storage.UserFilter) ([]model.User, error) {
+func (i *Postgres) GetAllUsers(ctx context.Context, filter storage.UserFilter) ([]model.User, error) {
 	arr := make([]model.User, 0)
-	query := "SELECT id, username, firstname, lastname, password, location FROM users WHERE 1=1"
-	v := reflect.ValueOf(filter)
-
-	for i := 0; i < v.NumField(); i++ {
-		val := v.Field(i).Interface().(string)
-		if len(val) > 0 {
-			query += " and " + v.Type().Field(i).Name + "=" + "'" + val + "'"
-		}
-	}
+	user := model.User{}
+	var l string
+	fields := filter.SelectFields()
+	columns, dest := userColumns(fields, &user, &l)
+	query, args := usersQuery(filter, columns)
 
-	rows, err := i.pool.Query(context.Background(), query)
+	rows, err := i.db.Query(ctx, query, args...)
 	if err == pgx.ErrNoRows {
 		return arr, nil
 	}
@@ -116,18 +141,19 @@ func (i *Postgres) GetAllUsers(filter storage.UserFilter) ([]model.User, error)
 		return arr, fmt.Errorf("Unable to SELECT: %v", err)
 	}
 
-	var l string
 	for rows.Next() {
-		user := model.User{}
-		err := rows.Scan(&user.ID, &user.UserName, &user.FirstName, &user.LastName, &user.Password, &l)
+		user, l = model.User{}, ""
+		err := rows.Scan(dest...)
 		if err != nil {
 			return arr, fmt.Errorf("Unable to SELECT: %v", err)
 		}
-		location, err := time.LoadLocation(l)
-		if err != nil {
-			return arr, fmt.Errorf("Unable to convert location: %v", err)
+		if fields == nil || l != "" {
+			location, err := time.LoadLocation(l)
+			if err != nil {
+				return arr, fmt.Errorf("Unable to convert location: %v", err)
+			}
+			user.Location = model.CustomLocation{Location: location}
 		}
-		user.Location = model.CustomLocation{Location: location}
 
 		arr = append(arr, user)
 	}
@@ -135,13 +161,42 @@ func (i *Postgres) GetAllUsers(filter storage.UserFilter) ([]model.User, error)
 	return arr, nil
 }
 
+// usersQuery builds SELECT of given columns from users matching filter.
+func usersQuery(filter storage.UserFilter, columns string) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select(columns).From("users")
+
+	if len(filter.IDs) > 0 {
+		sb.Where("id = ANY(" + sb.Var(validIDs(filter.IDs)) + "::uuid[])")
+	}
+	if len(filter.UserName) > 0 {
+		sb.Where(sb.Equal("lower(username)", strings.ToLower(filter.UserName)))
+	}
+	if len(filter.Query) > 0 {
+		prefix := strings.ToLower(likeEscaper.Replace(filter.Query)) + "%"
+		sb.Where(sb.Or(
+			sb.Like("lower(username)", prefix),
+			sb.Like("lower(firstname)", prefix),
+			sb.Like("lower(lastname)", prefix),
+		))
+	}
+	if len(filter.AfterID) > 0 {
+		sb.Where("id > " + sb.Var(filter.AfterID) + "::uuid")
+	}
+	sb.OrderBy("id")
+	if filter.Limit > 0 {
+		sb.Limit(filter.Limit)
+	}
+	return sb.Build()
+}
+
 // GetItem gets todo from db.
-func (i *Postgres) GetItem(id string) (model.TodoItem, error) {
+func (i *Postgres) GetItem(ctx context.Context, id string) (model.TodoItem, error) {
 	todo := model.TodoItem{}
 
-	err := i.pool.QueryRow(context.Background(),
-		"SELECT id, name, date, status, userid FROM todos WHERE id = $1",
-		id).Scan(&todo.ID, &todo.Name, &todo.Date, &todo.Status, &todo.UserID)
+	err := i.db.QueryRow(ctx,
+		"SELECT id, name, date, allday, status, position, version, userid FROM todos WHERE id = $1"+i.forUpdate(),
+		id).Scan(&todo.ID, &todo.Name, &todo.Date, &todo.AllDay, &todo.Status, &todo.Position, &todo.Version, &todo.UserID)
 
 	if err == pgx.ErrNoRows {
 		return model.TodoItem{}, nil
@@ -153,7 +208,7 @@ func (i *Postgres) GetItem(id string) (model.TodoItem, error) {
 
 	user := model.User{}
 	var l string
-	err = i.pool.QueryRow(context.Background(),
+	err = i.db.QueryRow(ctx,
 		"SELECT id, username, firstname, lastname, password, location FROM users WHERE id = $1",
 		todo.UserID).Scan(&user.ID, &user.UserName, &user.FirstName, &user.LastName, &user.Password, &l)
 
@@ -168,31 +223,48 @@ func (i *Postgres) GetItem(id string) (model.TodoItem, error) {
 	if err != nil {
 		return model.TodoItem{}, fmt.Errorf("cant load location")
 	}
-	todo.Date = todo.Date.In(location)
+	todo.Date = todo.DateIn(location)
 
 	return todo, nil
 }
 
-// UpdateItem updates todo todo in db.
-func (i *Postgres) UpdateItem(item model.TodoItem) error {
+// UpdateItem updates todo todo in db and increments its version.
+func (i *Postgres) UpdateItem(ctx context.Context, item model.TodoItem) error {
 	if item.Date.IsZero() {
 		item.Date = time.Now().UTC()
 	} else {
 		item.Date = item.Date.UTC()
 	}
 
-	_, err := i.pool.Exec(context.Background(),
-		"UPDATE todos SET name=$2, date=$3, status=$4, userid=$5 WHERE id = $1",
-		item.ID, item.Name, item.Date, item.Status, item.UserID)
+	_, err := i.db.Exec(ctx,
+		"UPDATE todos SET name=$2, date=$3, status=$4, userid=$5, position=$6, allday=$7, version=version+1, "+completedColumn+" WHERE id = $1",
+		item.ID, item.Name, item.Date, item.Status, item.UserID, item.Position, item.AllDay, model.StatusDone, time.Now().UTC())
 	if err != nil {
 		return fmt.Errorf("Unable to update: %v", err)
 	}
 	return nil
 }
 
+// UpdateItemPositions sets positions of todos in db with single batch.
+func (i *Postgres) UpdateItemPositions(ctx context.Context, items []model.TodoItem) error {
+	b := &pgx.Batch{}
+	for _, item := range items {
+		b.Queue("UPDATE todos SET position = $2 WHERE id = $1", item.ID, item.Position)
+	}
+
+	br := i.db.SendBatch(ctx, b)
+	defer br.Close()
+	for range items {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("Unable to update: %v", err)
+		}
+	}
+	return br.Close()
+}
+
 // DeleteItem deletes todo in db.
-func (i *Postgres) DeleteItem(id string) error {
-	_, err := i.pool.Exec(context.Background(), "DELETE FROM todos WHERE id = $1", id)
+func (i *Postgres) DeleteItem(ctx context.Context, id string) error {
+	_, err := i.db.Exec(ctx, "DELETE FROM todos WHERE id = $1", id)
 	if err != nil {
 		return fmt.Errorf("Unable to DELETE: %v", err)
 	}
@@ -200,7 +272,7 @@ func (i *Postgres) DeleteItem(id string) error {
 }
 
 // AddItem adds todo to db.
-func (i *Postgres) AddItem(item model.TodoItem) (string, error) {
+func (i *Postgres) AddItem(ctx context.Context, item model.TodoItem) (string, error) {
 	u := uuid.NewV4().String()
 	item.ID = u
 	if item.Status == "" {
@@ -212,25 +284,60 @@ func (i *Postgres) AddItem(item model.TodoItem) (string, error) {
 	} else {
 		item.Date = item.Date.UTC()
 	}
+	created, completed := completionTimes(item)
 
-	err := i.pool.QueryRow(context.Background(),
-		"INSERT INTO todos (id, name, date, status, userid) VALUES ($1, $2, $3, $4, $5) RETURNING id",
-		item.ID, item.Name, item.Date, item.Status, item.UserID).Scan(&item.ID)
+	err := i.db.QueryRow(ctx,
+		"INSERT INTO todos (id, name, date, status, userid, position, allday, created, completed) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id",
+		item.ID, item.Name, item.Date, item.Status, item.UserID, item.Position, item.AllDay, created, completed).Scan(&item.ID)
 	if err != nil {
 		return "", fmt.Errorf("Unable to INSERT: %v", err)
 	}
 	return u, nil
 }
 
+// completedColumn sets completion time of updated todo, given status $4, done status $8 and now $9.
+// It is kept while todo stays done and cleared once it is reopened.
+const completedColumn = "completed = CASE WHEN $4 = $8 THEN coalesce(completed, $9) END"
+
+// completionTimes returns creation and completion time of added todo,
+// completion is nil unless todo is done.
+func completionTimes(item model.TodoItem) (time.Time, *time.Time) {
+	now := time.Now().UTC()
+	created := now
+	if !item.Created.IsZero() {
+		created = item.Created.UTC()
+	}
+	if item.Status != model.StatusDone {
+		return created, nil
+	}
+	completed := now
+	if !item.Completed.IsZero() {
+		completed = item.Completed.UTC()
+	}
+	return created, &completed
+}
+
+// ReassignItems moves todos of one user to another in db.
+func (i *Postgres) ReassignItems(ctx context.Context, fromUserID, toUserID string) error {
+	_, err := i.db.Exec(ctx, "UPDATE todos SET userid = $2, version = version + 1 WHERE userid = $1", fromUserID, toUserID)
+	if err != nil {
+		return fmt.Errorf("Unable to update: %v", err)
+	}
+	return nil
+}
+
 // GetAllItems gets all todos from db.
-func (i *Postgres) GetAllItems(filter storage.TodoFilter) ([]model.TodoItem, error) {
+func (i *Postgres) GetAllItems(ctx context.Context, filter storage.TodoFilter) ([]model.TodoItem, error) {
 	arr := make([]model.TodoItem, 0)
 
 	user := model.User{}
 	var l string
-	err := i.pool.QueryRow(context.Background(),
+	err := i.db.QueryRow(ctx,
 		"SELECT id, username, firstname, lastname, password, location FROM users WHERE id = $1",
 		filter.UserID).Scan(&user.ID, &user.UserName, &user.FirstName, &user.LastName, &user.Password, &l)
+	if err == pgx.ErrNoRows {
+		return arr, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Unable to SELECT: %v", err)
 	}
@@ -240,22 +347,14 @@ func (i *Postgres) GetAllItems(filter storage.TodoFilter) ([]model.TodoItem, err
 		return arr, fmt.Errorf("cant load location")
 	}
 
-	query := "SELECT id, name, date, status, userid FROM todos WHERE 1=1"
-
-	if len(filter.UserID) > 0 {
-		query += " and userid = '" + filter.UserID + "'"
-	}
-	if len(filter.Status) > 0 {
-		query += " and status = '" + filter.Status + "'"
-	}
-	if filter.FromDate != nil {
-		query += " and date >= '" + filter.FromDate.UTC().Format(time.RFC3339) + "'"
-	}
-	if filter.ToDate != nil {
-		query += " and date <= '" + filter.ToDate.UTC().Format(time.RFC3339) + "'"
+	item := model.TodoItem{}
+	columns, dest := todoColumns(filter.SelectFields(), &item)
+	query, args := itemsQuery(filter, columns, location)
+	if len(filter.IDs) > 0 {
+		query += i.forUpdate()
 	}
 
-	rows, err := i.pool.Query(context.Background(), query)
+	rows, err := i.db.Query(ctx, query, args...)
 	if err == pgx.ErrNoRows {
 		return arr, nil
 	}
@@ -264,14 +363,47 @@ func (i *Postgres) GetAllItems(filter storage.TodoFilter) ([]model.TodoItem, err
 	}
 
 	for rows.Next() {
-		item := model.TodoItem{}
-		err := rows.Scan(&item.ID, &item.Name, &item.Date, &item.Status, &item.UserID)
+		item = model.TodoItem{}
+		err := rows.Scan(dest...)
 		if err != nil {
 			return arr, fmt.Errorf("Unable to SELECT: %v", err)
 		}
-		item.Date = item.Date.In(location)
+		item.Date = item.DateIn(location)
 		arr = append(arr, item)
 	}
 
 	return arr, nil
 }
+
+// itemsQuery builds SELECT of given columns from todos matching filter.
+func itemsQuery(filter storage.TodoFilter, columns string, location *time.Location) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select(columns).From("todos")
+
+	if len(filter.UserID) > 0 {
+		sb.Where(sb.Equal("userid", filter.UserID))
+	}
+	if len(filter.IDs) > 0 {
+		sb.Where("id = ANY(" + sb.Var(validIDs(filter.IDs)) + "::uuid[])")
+	}
+	if len(filter.Status) > 0 {
+		sb.Where(sb.Equal("status", filter.Status))
+	}
+	if filter.FromDate != nil {
+		sb.Where(sb.GreaterEqualThan("date", filter.FromDate.UTC()))
+	}
+	if filter.ToDate != nil {
+		sb.Where(sb.LessEqualThan("date", filter.ToDate.UTC()))
+	}
+	if filter.Query != nil {
+		sb.Where(compileQuery(filter.Query, location, sb.Var))
+	}
+	if filter.After != nil {
+		sb.Where(afterCondition(*filter.After, filter.SortFields(), sb.Var))
+	}
+	sb.OrderBy(orderBy(filter.SortFields())...)
+	if filter.Limit > 0 {
+		sb.Limit(filter.Limit)
+	}
+	return sb.Build()
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"strings"
+	"time"
+
+	"todo/storage/query"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// compileQuery compiles parsed query to SQL condition over todos table.
+// Values are bound through arg, which returns placeholder for the value.
+func compileQuery(e query.Expr, location *time.Location, arg func(interface{}) string) string {
+	switch e := e.(type) {
+	case *query.And:
+		return "(" + compileQuery(e.Left, location, arg) + " AND " + compileQuery(e.Right, location, arg) + ")"
+	case *query.Or:
+		return "(" + compileQuery(e.Left, location, arg) + " OR " + compileQuery(e.Right, location, arg) + ")"
+	case *query.Not:
+		return "NOT " + compileQuery(e.Expr, location, arg)
+	case *query.Comparison:
+		return compileComparison(e, location, arg)
+	default:
+		return "TRUE"
+	}
+}
+
+func compileComparison(c *query.Comparison, location *time.Location, arg func(interface{}) string) string {
+	switch c.Field {
+	case query.FieldStatus, query.FieldName:
+		column := c.Field
+		switch c.Op {
+		case query.OpEq:
+			return column + " = " + arg(c.Value)
+		case query.OpNe:
+			return column + " <> " + arg(c.Value)
+		case query.OpContains:
+			return column + " ILIKE '%' || " + arg(likeEscaper.Replace(c.Value)) + " || '%'"
+		}
+	case query.FieldDate:
+		start, end := c.Range(location)
+		start, end = start.UTC(), end.UTC()
+		switch c.Op {
+		case query.OpEq:
+			return "(date >= " + arg(start) + " AND date < " + arg(end) + ")"
+		case query.OpNe:
+			return "(date < " + arg(start) + " OR date >= " + arg(end) + ")"
+		case query.OpLt:
+			return "date < " + arg(start)
+		case query.OpLe:
+			return "date < " + arg(end)
+		case query.OpGt:
+			return "date >= " + arg(end)
+		case query.OpGe:
+			return "date >= " + arg(start)
+		}
+	}
+	return "FALSE"
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/query"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hostile values must reach database only as bound arguments.
+var hostile = []string{
+	`x' OR '1'='1`,
+	`'; DROP TABLE todos; --`,
+	`$1) OR (1=1`,
+	`\' UNION SELECT password FROM users --`,
+}
+
+func assertBound(t *testing.T, sql string, args []interface{}, value string) {
+	t.Helper()
+	assert.NotContains(t, sql, value)
+	assert.NotContains(t, sql, "'1'='1")
+	assert.NotContains(t, sql, "DROP")
+	assert.NotContains(t, sql, "UNION")
+	assert.Contains(t, args, value)
+}
+
+func TestItemsQuery(t *testing.T) {
+	l, _ := time.LoadLocation("America/New_York")
+
+	t.Run("Plain filter", func(t *testing.T) {
+		from := time.Date(2026, 10, 1, 0, 0, 0, 0, l)
+		sql, args := itemsQuery(storage.TodoFilter{UserID: "user", Status: "new", FromDate: &from, Limit: 10}, "id, name", l)
+		assert.Equal(t, "SELECT id, name FROM todos WHERE userid = $1 AND status = $2 AND date >= $3 ORDER BY date, id LIMIT 10", sql)
+		assert.Equal(t, []interface{}{"user", "new", from.UTC()}, args)
+	})
+
+	t.Run("IDs filter", func(t *testing.T) {
+		id := "c9ae2a6e-3d6a-4b8e-9a5c-2a4d1f0e7b61"
+		sql, args := itemsQuery(storage.TodoFilter{UserID: "user", IDs: []string{id, "2"}}, "id", l)
+		assert.Equal(t, "SELECT id FROM todos WHERE userid = $1 AND id = ANY($2::uuid[]) ORDER BY date, id", sql)
+		assert.Equal(t, []interface{}{"user", []string{id}}, args, "malformed ids are dropped")
+	})
+
+	for _, value := range hostile {
+		t.Run("Hostile filter "+value, func(t *testing.T) {
+			sql, args := itemsQuery(storage.TodoFilter{UserID: value}, "id", l)
+			assertBound(t, sql, args, value)
+
+			sql, args = itemsQuery(storage.TodoFilter{Status: value}, "id", l)
+			assertBound(t, sql, args, value)
+		})
+
+		t.Run("Hostile query "+value, func(t *testing.T) {
+			e := &query.Or{
+				Left:  &query.Comparison{Field: query.FieldStatus, Op: query.OpEq, Value: value},
+				Right: &query.Comparison{Field: query.FieldName, Op: query.OpContains, Value: value},
+			}
+			sql, args := itemsQuery(storage.TodoFilter{Query: e}, "id", l)
+			assertBound(t, sql, args, value)
+			assert.Contains(t, args, likeEscaper.Replace(value))
+		})
+
+		t.Run("Hostile cursor "+value, func(t *testing.T) {
+			after := model.TodoItem{ID: value, Name: value, Status: value}
+			sort := []storage.SortField{{Field: "name"}, {Field: "status", Desc: true}}
+			sql, args := itemsQuery(storage.TodoFilter{After: &after, Sort: sort}, "id", l)
+			assertBound(t, sql, args, value)
+			assert.Equal(t, strings.Count(sql, "$"), len(args))
+		})
+	}
+}
+
+func TestUsersQuery(t *testing.T) {
+	t.Run("Plain filter", func(t *testing.T) {
+		sql, args := usersQuery(storage.UserFilter{UserName: "John", AfterID: "id", Limit: 5}, "id, username")
+		assert.Equal(t, "SELECT id, username FROM users WHERE lower(username) = $1 AND id > $2::uuid ORDER BY id LIMIT 5", sql)
+		assert.Equal(t, []interface{}{"john", "id"}, args)
+	})
+
+	t.Run("Prefix escapes wildcards", func(t *testing.T) {
+		_, args := usersQuery(storage.UserFilter{Query: "Jo%_"}, "id")
+		assert.Equal(t, []interface{}{`jo\%\_%`, `jo\%\_%`, `jo\%\_%`}, args)
+	})
+
+	for _, value := range hostile {
+		t.Run("Hostile filter "+value, func(t *testing.T) {
+			sql, args := usersQuery(storage.UserFilter{UserName: value}, "id")
+			assertBound(t, sql, args, strings.ToLower(value))
+
+			sql, args = usersQuery(storage.UserFilter{AfterID: value}, "id")
+			assertBound(t, sql, args, value)
+		})
+	}
+}
+
+func TestActivitiesAndNotificationsQuery(t *testing.T) {
+	for _, value := range hostile {
+		t.Run("Hostile filter "+value, func(t *testing.T) {
+			before := storage.Cursor{ID: value, Date: time.Now()}
+			sql, args := activitiesQuery(storage.ActivityFilter{UserID: value, Types: []string{value}, Before: &before})
+			assertBound(t, sql, args, value)
+			assert.Contains(t, args, []string{value})
+
+			sql, args = notificationsQuery(storage.NotificationFilter{UserID: value, Before: &before})
+			assertBound(t, sql, args, value)
+
+			sql, args = searchQuery(storage.TodoSearch{UserID: value, Query: value, Limit: 3})
+			assertBound(t, sql, args, value)
+			assert.Equal(t, []interface{}{value, value}, args)
+		})
+	}
+}
+
+func TestEscapeHeadline(t *testing.T) {
+	assert.Equal(t, "&lt;script&gt;<b>alert</b>(1)&lt;/script&gt;", escapeHeadline("<script>\x01alert\x02(1)</script>"))
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+
+	"github.com/huandu/go-sqlbuilder"
+)
+
+// SearchItems searches todos in db using full-text index.
+func (i *Postgres) SearchItems(ctx context.Context, search storage.TodoSearch) ([]model.TodoSearchResult, error) {
+	arr := make([]model.TodoSearchResult, 0)
+
+	var l string
+	err := i.db.QueryRow(ctx,
+		"SELECT location FROM users WHERE id = $1", search.UserID).Scan(&l)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	location, err := time.LoadLocation(l)
+	if err != nil {
+		return arr, fmt.Errorf("cant load location")
+	}
+
+	query, args := searchQuery(search)
+
+	rows, err := i.db.Query(ctx, query, args...)
+	if err != nil {
+		return arr, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		r := model.TodoSearchResult{}
+		var rank float32
+		err := rows.Scan(&r.Todo.ID, &r.Todo.Name, &r.Todo.Date, &r.Todo.AllDay, &r.Todo.Status, &r.Todo.Position, &r.Todo.Version, &r.Todo.UserID, &rank, &r.Snippet)
+		if err != nil {
+			return arr, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		r.Rank = float64(rank)
+		r.Snippet = escapeHeadline(r.Snippet)
+		r.Todo.Date = r.Todo.DateIn(location)
+		arr = append(arr, r)
+	}
+
+	return arr, rows.Err()
+}
+
+// searchQuery builds full-text SELECT of todos matching search.
+func searchQuery(search storage.TodoSearch) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select("id", "name", "date", "allday", "status", "position", "version", "userid", "ts_rank(search, q)",
+		"ts_headline('simple', translate(name, chr(1) || chr(2), ''), q, "+
+			"'StartSel=' || chr(1) || ', StopSel=' || chr(2) || ', HighlightAll=TRUE')")
+	sb.From("todos", "plainto_tsquery('simple', "+sb.Var(search.Query)+") q")
+	sb.Where(sb.Equal("userid", search.UserID), "search @@ q")
+	sb.OrderBy("ts_rank(search, q) DESC", "id")
+	if search.Limit > 0 {
+		sb.Limit(search.Limit)
+	}
+	return sb.Build()
+}
+
+// headlineMarks replaces control characters ts_headline puts around matched
+// words with <b></b> once the rest of the name is HTML-escaped.
+var headlineMarks = strings.NewReplacer("\x01", "<b>", "\x02", "</b>")
+
+// escapeHeadline HTML-escapes ts_headline output so only the highlight markup
+// is left unescaped.
+func escapeHeadline(headline string) string {
+	return headlineMarks.Replace(html.EscapeString(headline))
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"strings"
+
+	"todo/model"
+	"todo/storage"
+)
+
+// sortColumns maps sortable todo fields to SQL expressions.
+// Text columns use "C" collation to compare bytes like in-memory storage does.
+var sortColumns = map[string]string{
+	"name":     `name COLLATE "C"`,
+	"status":   `status COLLATE "C"`,
+	"date":     "date",
+	"position": "position",
+}
+
+// orderBy returns ORDER BY columns for sort fields with id as tie-breaker.
+func orderBy(fields []storage.SortField) []string {
+	columns := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		column := sortColumns[f.Field]
+		if f.Desc {
+			column += " DESC"
+		}
+		columns = append(columns, column)
+	}
+	return append(columns, "id")
+}
+
+// afterCondition returns condition matching todos placed after given todo in sort order.
+func afterCondition(after model.TodoItem, fields []storage.SortField, arg func(interface{}) string) string {
+	var conditions, equal []string
+	for _, f := range fields {
+		param := sortParam(after, f.Field, arg)
+		op := " > "
+		if f.Desc {
+			op = " < "
+		}
+		column := sortColumns[f.Field]
+		conditions = append(conditions, "("+strings.Join(append(equal, column+op+param), " and ")+")")
+		equal = append(equal, column+" = "+param)
+	}
+	conditions = append(conditions, "("+strings.Join(append(equal, "id > "+arg(after.ID)+"::uuid"), " and ")+")")
+	return "(" + strings.Join(conditions, " or ") + ")"
+}
+
+func sortParam(todo model.TodoItem, field string, arg func(interface{}) string) string {
+	switch field {
+	case "name":
+		return arg(todo.Name)
+	case "status":
+		return arg(todo.Status)
+	case "position":
+		return arg(todo.Position)
+	case "date":
+		return arg(todo.Date.UTC()) + "::timestamp"
+	}
+	return "NULL"
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+)
+
+// GetStats computes todo stats of user with aggregate queries.
+// Only non-empty buckets are returned.
+func (i *Postgres) GetStats(ctx context.Context, filter storage.StatsFilter) (model.Stats, error) {
+	stats := model.Stats{ByStatus: map[string]int{}}
+
+	rows, err := i.db.Query(ctx,
+		`SELECT status, count(*),
+			count(*) FILTER (WHERE status <> $2 AND (allday AND date < $4 OR NOT allday AND date < $3)),
+			count(*) FILTER (WHERE created IS NULL)
+		FROM todos WHERE userid = $1 GROUP BY status`,
+		filter.UserID, model.StatusDone, filter.Now.UTC(), filter.AllDayToday())
+	if err != nil {
+		return stats, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	for rows.Next() {
+		var status string
+		var count, overdue, untracked int
+		if err := rows.Scan(&status, &count, &overdue, &untracked); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		stats.ByStatus[status] = count
+		stats.Overdue += overdue
+		stats.Untracked += untracked
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+
+	rows, err = i.db.Query(ctx,
+		`SELECT date_trunc($2, (date AT TIME ZONE 'UTC') AT TIME ZONE $3) AS bucket,
+			count(*) FILTER (WHERE created), count(*) FILTER (WHERE NOT created)
+		FROM (
+			SELECT created AS date, TRUE AS created FROM todos WHERE userid = $1
+			UNION ALL
+			SELECT completed, FALSE FROM todos WHERE userid = $1
+		) t
+		WHERE date >= $4 AND date < $5
+		GROUP BY bucket ORDER BY bucket`,
+		filter.UserID, filter.Bucket, filter.Location.String(), filter.From.UTC(), filter.To.UTC())
+	if err != nil {
+		return stats, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	for rows.Next() {
+		var start time.Time
+		b := model.StatsBucket{}
+		if err := rows.Scan(&start, &b.Created, &b.Completed); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		// bucket is local wall clock time without zone
+		b.Start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, filter.Location)
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+
+	err = i.db.QueryRow(ctx,
+		`SELECT coalesce(avg(extract(epoch FROM completed - created)), 0)::float8
+		FROM todos
+		WHERE userid = $1 AND completed >= $2 AND completed < $3 AND created IS NOT NULL`,
+		filter.UserID, filter.From.UTC(), filter.To.UTC()).Scan(&stats.AvgTimeToComplete)
+	if err != nil {
+		return stats, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+
+	return stats, nil
+}
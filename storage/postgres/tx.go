@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"todo/storage"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// querier is implemented by both pgxpool.Pool and pgx.Tx.
+type querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// WithTx runs fn in db transaction. Nested calls use savepoints.
+// Rows read by single record getters inside fn stay locked until the end of transaction,
+// except users which are locked explicitly with LockUsers.
+func (i *Postgres) WithTx(ctx context.Context, fn func(tx storage.Storage) error) error {
+	tx, err := i.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("Unable to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Postgres{db: tx, tx: true}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("Unable to commit transaction: %v", err)
+	}
+	return nil
+}
+
+// forUpdate returns locking clause for record read in transaction.
+func (i *Postgres) forUpdate() string {
+	if i.tx {
+		return " FOR UPDATE"
+	}
+	return ""
+}
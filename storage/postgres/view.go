@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"todo/model"
+
+	"github.com/jackc/pgx/v4"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AddView adds view to db.
+func (i *Postgres) AddView(ctx context.Context, view model.View) (string, error) {
+	view.ID = uuid.NewV4().String()
+
+	_, err := i.db.Exec(ctx,
+		"INSERT INTO views (id, userid, name, status, fromdate, todate, query, sort) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		view.ID, view.UserID, view.Name, view.Status, utcTime(view.FromDate), utcTime(view.ToDate), view.Query, view.Sort)
+	if err != nil {
+		return "", fmt.Errorf("Unable to INSERT: %v", err)
+	}
+	return view.ID, nil
+}
+
+// DeleteView deletes view in db.
+func (i *Postgres) DeleteView(ctx context.Context, id string) error {
+	_, err := i.db.Exec(ctx, "DELETE FROM views WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("Unable to DELETE: %v", err)
+	}
+	return nil
+}
+
+// UpdateView updates view in db.
+func (i *Postgres) UpdateView(ctx context.Context, view model.View) error {
+	_, err := i.db.Exec(ctx,
+		"UPDATE views SET name=$2, status=$3, fromdate=$4, todate=$5, query=$6, sort=$7 WHERE id = $1",
+		view.ID, view.Name, view.Status, utcTime(view.FromDate), utcTime(view.ToDate), view.Query, view.Sort)
+	if err != nil {
+		return fmt.Errorf("Unable to update: %v", err)
+	}
+	return nil
+}
+
+// GetView gets view from db.
+func (i *Postgres) GetView(ctx context.Context, id string) (model.View, error) {
+	view := model.View{}
+	err := i.db.QueryRow(ctx,
+		"SELECT id, userid, name, status, fromdate, todate, query, sort FROM views WHERE id = $1"+i.forUpdate(),
+		id).Scan(&view.ID, &view.UserID, &view.Name, &view.Status, &view.FromDate, &view.ToDate, &view.Query, &view.Sort)
+	if err == pgx.ErrNoRows {
+		return model.View{}, nil
+	}
+	if err != nil {
+		return model.View{}, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	return view, nil
+}
+
+// GetAllViews gets all views of user from db ordered by name.
+func (i *Postgres) GetAllViews(ctx context.Context, userID string) ([]model.View, error) {
+	arr := make([]model.View, 0)
+	rows, err := i.db.Query(ctx,
+		"SELECT id, userid, name, status, fromdate, todate, query, sort FROM views WHERE userid = $1 ORDER BY name, id",
+		userID)
+	if err != nil {
+		return arr, fmt.Errorf("Unable to SELECT: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		view := model.View{}
+		err := rows.Scan(&view.ID, &view.UserID, &view.Name, &view.Status, &view.FromDate, &view.ToDate, &view.Query, &view.Sort)
+		if err != nil {
+			return arr, fmt.Errorf("Unable to SELECT: %v", err)
+		}
+		arr = append(arr, view)
+	}
+	return arr, rows.Err()
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
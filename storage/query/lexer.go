@@ -0,0 +1,111 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int // 1-based position in query
+}
+
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// lex splits query into tokens. Last token is always tokenEOF.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	pos := 1
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		start := pos
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			pos++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: start})
+			i += size
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: start})
+			i += size
+			pos++
+		case r == '"':
+			var b strings.Builder
+			i += size
+			pos++
+			closed := false
+			for i < len(input) {
+				r, size = utf8.DecodeRuneInString(input[i:])
+				i += size
+				pos++
+				if r == '"' {
+					closed = true
+					break
+				}
+				if r == '\\' && i < len(input) {
+					r, size = utf8.DecodeRuneInString(input[i:])
+					i += size
+					pos++
+				}
+				b.WriteRune(r)
+			}
+			if !closed {
+				return nil, &Error{Pos: start, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: b.String(), pos: start})
+		case strings.ContainsRune(":=!~<>", r):
+			op := string(r)
+			if i+1 < len(input) && input[i+1] == '=' && strings.ContainsRune("!<>", r) {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: start, Msg: "unexpected \"!\", did you mean !=?"}
+			}
+			tokens = append(tokens, token{kind: tokenOp, value: op, pos: start})
+			i += len(op)
+			pos += len(op)
+		default:
+			var b strings.Builder
+			for i < len(input) {
+				r, size = utf8.DecodeRuneInString(input[i:])
+				if unicode.IsSpace(r) || strings.ContainsRune("()\":=!~<>", r) {
+					break
+				}
+				b.WriteRune(r)
+				i += size
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: b.String(), pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: pos}), nil
+}
@@ -0,0 +1,199 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Limits of query size, so parsing and compiling recursion stays shallow.
+const (
+	MaxLength = 2048 // bytes
+	MaxDepth  = 32   // nested parentheses and NOT operators
+)
+
+// Parse parses and validates query.
+func Parse(input string) (Expr, error) {
+	if len(input) > MaxLength {
+		return nil, &Error{Pos: MaxLength + 1, Msg: fmt.Sprintf("query is longer than %d bytes", MaxLength)}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: p.peek().pos, Msg: "query is empty"}
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	cur    int
+	depth  int // open parentheses and NOT operators around current token
+}
+
+// enter fails if t opens group nested deeper than MaxDepth, leave must follow successful enter.
+func (p *parser) enter(t token) error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("query is nested deeper than %d levels", MaxDepth)}
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.cur]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.cur]
+	if t.kind != tokenEOF {
+		p.cur++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.isKeyword("AND"):
+			p.next()
+		case (t.kind == tokenWord && !t.isKeyword("OR")) || t.kind == tokenLParen:
+			// implicit AND
+		default:
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if t := p.peek(); t.isKeyword("NOT") {
+		p.next()
+		if err := p.enter(t); err != nil {
+			return nil, err
+		}
+		e, err := p.parseNot()
+		p.leave()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenLParen:
+		if err := p.enter(t); err != nil {
+			return nil, err
+		}
+		e, err := p.parseOr()
+		p.leave()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.kind != tokenRParen {
+			return nil, &Error{Pos: c.pos, Msg: fmt.Sprintf("expected ) to close ( at position %d, got %s", t.pos, c)}
+		}
+		return e, nil
+	case t.kind == tokenWord && !t.isKeyword("AND") && !t.isKeyword("OR"):
+		return p.parseComparison(t)
+	default:
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected field or (, got %s", t)}
+	}
+}
+
+func (p *parser) parseComparison(field token) (Expr, error) {
+	name, ok := fieldAliases[strings.ToLower(field.value)]
+	if !ok {
+		return nil, &Error{Pos: field.pos, Msg: fmt.Sprintf("unknown field %q", field.value)}
+	}
+
+	op := p.next()
+	if op.kind != tokenOp {
+		return nil, &Error{Pos: op.pos, Msg: fmt.Sprintf("expected operator after %q, got %s", field.value, op)}
+	}
+	if op.value == ":" {
+		op.value = OpEq
+	}
+	if !opAllowed(name, op.value) {
+		return nil, &Error{Pos: op.pos, Msg: fmt.Sprintf("operator %s is not supported for field %q", op.value, field.value)}
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, &Error{Pos: value.pos, Msg: fmt.Sprintf("expected value for %q, got %s", field.value, value)}
+	}
+
+	c := &Comparison{Field: name, Op: op.value, Value: value.value, Pos: field.pos}
+	if name == FieldDate {
+		if err := c.parseDate(); err != nil {
+			return nil, &Error{Pos: value.pos, Msg: err.Error()}
+		}
+	}
+	return c, nil
+}
+
+func (c *Comparison) parseDate() error {
+	if d, err := time.Parse("2006-01-02", c.Value); err == nil {
+		c.date, c.dateOnly = d, true
+		return nil
+	}
+	d, err := time.Parse(time.RFC3339, c.Value)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC3339", c.Value)
+	}
+	c.date = d
+	return nil
+}
+
+func opAllowed(field, op string) bool {
+	for _, allowed := range fieldOps[field] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
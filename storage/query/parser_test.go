@@ -0,0 +1,89 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Parse precedence", func(t *testing.T) {
+		e, err := Parse(`status:done AND (name~"pay invoice" OR due<2026-11-01) NOT status:archived`)
+		assert.NoError(t, err)
+
+		and, ok := e.(*And)
+		assert.True(t, ok)
+		assert.IsType(t, &Not{}, and.Right)
+
+		inner, ok := and.Left.(*And)
+		assert.True(t, ok)
+		assert.Equal(t, &Comparison{Field: FieldStatus, Op: OpEq, Value: "done", Pos: 1}, inner.Left)
+
+		or, ok := inner.Right.(*Or)
+		assert.True(t, ok)
+		assert.Equal(t, &Comparison{Field: FieldName, Op: OpContains, Value: "pay invoice", Pos: 18}, or.Left)
+		date, ok := or.Right.(*Comparison)
+		assert.True(t, ok)
+		assert.Equal(t, FieldDate, date.Field)
+		assert.Equal(t, OpLt, date.Op)
+	})
+
+	t.Run("Date range", func(t *testing.T) {
+		e, err := Parse("due>=2026-11-01")
+		assert.NoError(t, err)
+
+		l, _ := time.LoadLocation("America/New_York")
+		start, end := e.(*Comparison).Range(l)
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, l), start)
+		assert.Equal(t, time.Date(2026, 11, 2, 0, 0, 0, 0, l), end)
+		assert.Equal(t, 25*time.Hour, end.Sub(start))
+
+		e, err = Parse(`date="2026-11-01T10:00:00Z"`)
+		assert.NoError(t, err)
+		start, _ = e.(*Comparison).Range(l)
+		assert.Equal(t, time.Date(2026, 11, 1, 10, 0, 0, 0, time.UTC), start)
+	})
+
+	errorTests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"", 1, "query is empty"},
+		{"tag:personal", 1, `unknown field "tag"`},
+		{"status~done", 7, `operator ~ is not supported for field "status"`},
+		{"status:done AND", 16, "expected field or (, got end of query"},
+		{"(status:done", 13, "expected ) to close ( at position 1, got end of query"},
+		{"due<tomorrow", 5, `invalid date "tomorrow", expected YYYY-MM-DD or RFC3339`},
+		{`name:"invoice`, 6, "unterminated string"},
+		{"status done", 8, `expected operator after "status", got "done"`},
+		{"status:done)", 12, `unexpected ")"`},
+	}
+	for _, tt := range errorTests {
+		t.Run("Error "+tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			assert.Equal(t, &Error{Pos: tt.pos, Msg: tt.msg}, err)
+		})
+	}
+
+	t.Run("Nesting limit", func(t *testing.T) {
+		_, err := Parse(strings.Repeat("(", MaxDepth) + "status:done" + strings.Repeat(")", MaxDepth))
+		assert.NoError(t, err)
+		_, err = Parse(strings.Repeat("NOT ", MaxDepth) + "status:done")
+		assert.NoError(t, err)
+
+		_, err = Parse(strings.Repeat("(", MaxDepth+1) + "status:done" + strings.Repeat(")", MaxDepth+1))
+		assert.Equal(t, &Error{Pos: MaxDepth + 1, Msg: "query is nested deeper than 32 levels"}, err)
+		_, err = Parse(strings.Repeat("(NOT ", MaxDepth) + "status:done")
+		assert.Equal(t, &Error{Pos: MaxDepth/2*5 + 1, Msg: "query is nested deeper than 32 levels"}, err)
+	})
+
+	t.Run("Length limit", func(t *testing.T) {
+		_, err := Parse(strings.Repeat("(", 2_000_000))
+		assert.Equal(t, &Error{Pos: MaxLength + 1, Msg: "query is longer than 2048 bytes"}, err)
+		_, err = Parse(strings.Repeat("(", MaxLength))
+		assert.Equal(t, &Error{Pos: MaxDepth + 1, Msg: "query is nested deeper than 32 levels"}, err)
+	})
+}
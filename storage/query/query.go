@@ -0,0 +1,104 @@
+// Package query implements filter query language for todos, e.g.
+//
+//	status:done AND (name~"invoice" OR due<2026-11-01) NOT status:archived
+//
+// Terms are comparisons of a field with a value joined by AND, OR and NOT.
+// Terms written one after another without operator are joined by AND.
+// Values containing spaces, parentheses or operator characters (e.g. RFC3339
+// dates) must be quoted.
+package query
+
+import (
+	"fmt"
+	"time"
+)
+
+// Supported fields.
+const (
+	FieldStatus = "status"
+	FieldName   = "name"
+	FieldDate   = "date"
+)
+
+// Supported operators. OpEq and ":" are the same operator.
+const (
+	OpEq       = "="
+	OpNe       = "!="
+	OpContains = "~"
+	OpLt       = "<"
+	OpLe       = "<="
+	OpGt       = ">"
+	OpGe       = ">="
+)
+
+// fieldAliases maps accepted field names to supported fields.
+var fieldAliases = map[string]string{
+	"status": FieldStatus,
+	"name":   FieldName,
+	"date":   FieldDate,
+	"due":    FieldDate,
+}
+
+// fieldOps lists operators allowed for field.
+var fieldOps = map[string][]string{
+	FieldStatus: {OpEq, OpNe},
+	FieldName:   {OpEq, OpNe, OpContains},
+	FieldDate:   {OpEq, OpNe, OpLt, OpLe, OpGt, OpGe},
+}
+
+// Expr represents node of parsed query.
+type Expr interface {
+	expr()
+}
+
+// And represents conjunction of two expressions.
+type And struct {
+	Left, Right Expr
+}
+
+// Or represents disjunction of two expressions.
+type Or struct {
+	Left, Right Expr
+}
+
+// Not represents negated expression.
+type Not struct {
+	Expr Expr
+}
+
+// Comparison represents comparison of todo field with value.
+type Comparison struct {
+	Field string
+	Op    string
+	Value string
+	Pos   int // 1-based position of field in query
+
+	date     time.Time
+	dateOnly bool
+}
+
+func (*And) expr()        {}
+func (*Or) expr()         {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
+
+// Range returns interval [start, end) matched by date value of comparison.
+// Values without time cover the whole day in location.
+func (c *Comparison) Range(location *time.Location) (time.Time, time.Time) {
+	if !c.dateOnly {
+		return c.date, c.date.Add(time.Nanosecond)
+	}
+	y, m, d := c.date.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, location)
+	return start, time.Date(y, m, d+1, 0, 0, 0, 0, location)
+}
+
+// Error represents query syntax or validation error.
+type Error struct {
+	Pos int // 1-based position in query
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("query error at position %d: %s", e.Pos, e.Msg)
+}
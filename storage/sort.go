@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"todo/model"
+)
+
+// SortField represents todo field used for ordering.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// sortableFields lists todo fields allowed in sort spec.
+var sortableFields = map[string]bool{
+	"name":     true,
+	"date":     true,
+	"status":   true,
+	"position": true,
+}
+
+// ParseSort parses comma separated sort spec like "-date,name".
+// Field prefixed with "-" is sorted in descending order.
+func ParseSort(spec string) ([]SortField, error) {
+	var fields []SortField
+	if strings.TrimSpace(spec) == "" {
+		return fields, nil
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		f := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !sortableFields[f.Field] {
+			return nil, fmt.Errorf("field %q is not sortable", f.Field)
+		}
+		if seen[f.Field] {
+			return nil, fmt.Errorf("field %q is used in sort more than once", f.Field)
+		}
+		seen[f.Field] = true
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// defaultSort is used when todo filter has no sort fields.
+var defaultSort = []SortField{{Field: "date"}}
+
+// SortFields returns sort order of filter, todos are ordered by date by default.
+func (f TodoFilter) SortFields() []SortField {
+	if len(f.Sort) == 0 {
+		return defaultSort
+	}
+	return f.Sort
+}
+
+// SortItems sorts todos by fields, ties are broken by ID.
+func SortItems(items []model.TodoItem, fields []SortField) {
+	sort.SliceStable(items, func(a, b int) bool {
+		return ItemBefore(items[a], items[b], fields)
+	})
+}
+
+// ItemBefore reports whether todo a goes before todo b when sorted by fields.
+func ItemBefore(a, b model.TodoItem, fields []SortField) bool {
+	for _, f := range fields {
+		c := compareItems(a, b, f.Field)
+		if c == 0 {
+			continue
+		}
+		if f.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return a.ID < b.ID
+}
+
+func compareItems(a, b model.TodoItem, field string) int {
+	switch field {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "status":
+		return strings.Compare(a.Status, b.Status)
+	case "position":
+		return a.Position - b.Position
+	case "date":
+		switch {
+		case a.Date.Before(b.Date):
+			return -1
+		case a.Date.After(b.Date):
+			return 1
+		}
+	}
+	return 0
+}
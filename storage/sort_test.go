@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+	"todo/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	fields, err := ParseSort("-date, name")
+	assert.NoError(t, err)
+	assert.Equal(t, []SortField{{Field: "date", Desc: true}, {Field: "name"}}, fields)
+
+	_, err = ParseSort("password")
+	assert.Error(t, err)
+
+	_, err = ParseSort("name,-name")
+	assert.Error(t, err)
+}
+
+func TestSortItems(t *testing.T) {
+	date := time.Date(2021, 10, 1, 12, 0, 0, 0, time.UTC)
+	items := []model.TodoItem{
+		{ID: "3", Name: "b", Date: date},
+		{ID: "1", Name: "a", Date: date},
+		{ID: "2", Name: "a", Date: date.Add(time.Hour)},
+	}
+
+	SortItems(items, []SortField{{Field: "date", Desc: true}})
+	assert.Equal(t, []string{"2", "1", "3"}, []string{items[0].ID, items[1].ID, items[2].ID})
+
+	SortItems(items, []SortField{{Field: "name"}})
+	assert.Equal(t, []string{"1", "2", "3"}, []string{items[0].ID, items[1].ID, items[2].ID})
+}
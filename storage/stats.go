@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"time"
+	"todo/model"
+)
+
+// StatsFilter represents stats query of one user.
+// Range is [From, To), buckets are truncated in Location.
+type StatsFilter struct {
+	UserID   string
+	From     time.Time
+	To       time.Time
+	Bucket   string // model.BucketDay or model.BucketWeek
+	Location *time.Location
+	Now      time.Time // todos due before Now and not done are overdue
+}
+
+// AllDayToday returns date all-day todos are stored at when due on local date of Now.
+// All-day todos are overdue once that date has passed in Location, like in smart lists.
+func (f StatsFilter) AllDayToday() time.Time {
+	return model.AllDayDate(f.Now.In(f.Location))
+}
+
+// BucketStart returns start of bucket containing t in location.
+// Weeks start on Monday.
+func BucketStart(t time.Time, bucket string, location *time.Location) time.Time {
+	t = t.In(location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+	if bucket == model.BucketWeek {
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
@@ -1,34 +1,138 @@
 package storage
 
 import (
+	"context"
 	"time"
 	"todo/model"
+	"todo/storage/query"
 )
 
 // Storage represent interface for storage types.
 type Storage interface {
-	AddItem(item model.TodoItem) (id string, err error)
-	DeleteItem(id string) error
-	UpdateItem(item model.TodoItem) error
-	GetItem(id string) (model.TodoItem, error)
-	GetAllItems(filter TodoFilter) ([]model.TodoItem, error)
+	AddItem(ctx context.Context, item model.TodoItem) (id string, err error)
+	DeleteItem(ctx context.Context, id string) error
+	UpdateItem(ctx context.Context, item model.TodoItem) error
+	GetItem(ctx context.Context, id string) (model.TodoItem, error)
+	GetAllItems(ctx context.Context, filter TodoFilter) ([]model.TodoItem, error)
+	SearchItems(ctx context.Context, search TodoSearch) ([]model.TodoSearchResult, error)
+	// AddItems, UpdateItems and DeleteItems change many todos at once, either all or none.
+	// Results are in order of arguments, missing todos are reported in results and left out.
+	AddItems(ctx context.Context, items []model.TodoItem) ([]ItemResult, error)
+	UpdateItems(ctx context.Context, items []model.TodoItem) ([]ItemResult, error)
+	DeleteItems(ctx context.Context, ids []string) ([]ItemResult, error)
+	// UpdateItemPositions sets board positions of todos, other fields and versions stay unchanged.
+	UpdateItemPositions(ctx context.Context, items []model.TodoItem) error
+	// ReassignItems moves all todos of one user to another.
+	ReassignItems(ctx context.Context, fromUserID, toUserID string) error
 
-	AddUser(user model.User) (id string, err error)
-	DeleteUser(id string) error
-	UpdateUser(user model.User) error
-	GetUser(id string) (model.User, error)
-	GetAllUsers(filter UserFilter) ([]model.User, error)
+	AddUser(ctx context.Context, user model.User) (id string, err error)
+	// DeleteUser deletes user with all its todos, activities, notifications, board and views.
+	DeleteUser(ctx context.Context, id string) error
+	UpdateUser(ctx context.Context, user model.User) error
+	GetUser(ctx context.Context, id string) (model.User, error)
+	GetAllUsers(ctx context.Context, filter UserFilter) ([]model.User, error)
+	// LockUsers locks users until the end of transaction, in order of id so that flows locking same users never deadlock.
+	LockUsers(ctx context.Context, ids ...string) error
+
+	AddActivity(ctx context.Context, activity model.Activity) (id string, err error)
+	GetAllActivities(ctx context.Context, filter ActivityFilter) ([]model.Activity, error)
+
+	AddNotification(ctx context.Context, notification model.Notification) (id string, err error)
+	GetNotification(ctx context.Context, id string) (model.Notification, error)
+	GetAllNotifications(ctx context.Context, filter NotificationFilter) ([]model.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int, error)
+	MarkNotificationRead(ctx context.Context, id string) error
+	MarkAllNotificationsRead(ctx context.Context, userID string) error
+	DeleteNotificationsBefore(ctx context.Context, date time.Time) error
+
+	GetStats(ctx context.Context, filter StatsFilter) (model.Stats, error)
+
+	GetBoard(ctx context.Context, userID string) (model.Board, error)
+	UpdateBoard(ctx context.Context, board model.Board) error
+	// LockBoard serialises board changes of user until the end of transaction.
+	LockBoard(ctx context.Context, userID string) error
+
+	AddView(ctx context.Context, view model.View) (id string, err error)
+	DeleteView(ctx context.Context, id string) error
+	UpdateView(ctx context.Context, view model.View) error
+	GetView(ctx context.Context, id string) (model.View, error)
+	GetAllViews(ctx context.Context, userID string) ([]model.View, error)
+
+	// AddEvent adds event to outbox, event added through tx is committed or discarded with the rest of tx.
+	AddEvent(ctx context.Context, event model.Event) (id string, err error)
+	// GetEvents returns up to limit undelivered events, oldest first.
+	GetEvents(ctx context.Context, limit int) ([]model.Event, error)
+	// DeleteEvent removes delivered event from outbox.
+	DeleteEvent(ctx context.Context, id string) error
+
+	// WithTx runs fn in transaction. Changes made through tx are discarded
+	// if fn returns error, otherwise they are committed.
+	WithTx(ctx context.Context, fn func(tx Storage) error) error
 }
 
 // TodoFilter represents filter struct for todos.
+// Todos are returned ordered by Sort fields, ties are broken by ID.
 type TodoFilter struct {
 	FromDate *time.Time // nil if empty ?
 	ToDate   *time.Time // nil if empty ?
 	Status   string
 	UserID   string
+	IDs      []string        // empty means any todo, todos selected by id are locked in transaction
+	Query    query.Expr      // nil if empty
+	Sort     []SortField     // empty means by date
+	After    *model.TodoItem // last todo of previous page, nil for the first page
+	Limit    int             // 0 means no limit
+	Fields   []string        // empty means all fields
+}
+
+// ItemResult represents outcome of single todo of batch.
+type ItemResult struct {
+	ID      string
+	Version int  // version after add or update
+	Missing bool // true if todo does not exist
+}
+
+// TodoSearch represents full-text search query for todos.
+// Results are ordered by rank, best matches first.
+type TodoSearch struct {
+	Query  string
+	UserID string
+	Limit  int // 0 means no limit
 }
 
 // UserFilter represents filter struct for users.
+// Users are returned ordered by id.
 type UserFilter struct {
-	UserName string
+	IDs      []string // empty means any user
+	UserName string   // case-insensitive
+	Query    string   // case-insensitive prefix of username, first or last name
+	AfterID  string   // empty for the first page
+	Limit    int      // 0 means no limit
+	Fields   []string // empty means all fields, password is loaded only then
+}
+
+// ActivityFilter represents filter struct for activity feed.
+// Activities are returned from newest to oldest.
+type ActivityFilter struct {
+	UserID string
+	Types  []string
+	Before *Cursor // nil for the first page
+	Limit  int     // 0 means no limit
+}
+
+// NotificationFilter represents filter struct for notifications.
+// Notifications are returned from newest to oldest.
+type NotificationFilter struct {
+	UserID     string
+	Type       string // empty means any type
+	TodoID     string // empty means any todo
+	UnreadOnly bool
+	Before     *Cursor // nil for the first page
+	Limit      int     // 0 means no limit
+}
+
+// Cursor represents position of record in listing ordered by date.
+type Cursor struct {
+	Date time.Time
+	ID   string
 }
@@ -0,0 +1,921 @@
+// Package storagetest provides conformance tests shared by storage.Storage implementations.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"todo/model"
+	"todo/storage"
+	"todo/storage/query"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// Factory returns empty storage for single test.
+type Factory func(t *testing.T) storage.Storage
+
+// Run runs conformance tests against storages returned by newStorage.
+// Dates are whole seconds, so backends may keep only microseconds.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"Todos", testTodos},
+		{"Todo filters", testTodoFilters},
+		{"Todo pages", testTodoPages},
+		{"Batches", testBatches},
+		{"Time zones", testTimeZones},
+		{"Users", testUsers},
+		{"User deletion", testUserDeletion},
+		{"Activities", testActivities},
+		{"Notifications", testNotifications},
+		{"Stats", testStats},
+		{"Board", testBoard},
+		{"Views", testViews},
+		{"Outbox", testOutbox},
+		{"Search", testSearch},
+		{"Not found", testNotFound},
+		{"Transactions", testTransactions},
+		{"Concurrency", testConcurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+var (
+	newYork, _ = time.LoadLocation("America/New_York")
+	tokyo, _   = time.LoadLocation("Asia/Tokyo")
+)
+
+func addUser(t *testing.T, s storage.Storage, name string, l *time.Location) string {
+	t.Helper()
+	id, err := s.AddUser(context.Background(), model.User{UserName: name, Password: "secret", Location: model.CustomLocation{Location: l}})
+	assert.NoError(t, err)
+	return id
+}
+
+func addItem(t *testing.T, s storage.Storage, todo model.TodoItem) string {
+	t.Helper()
+	id, err := s.AddItem(context.Background(), todo)
+	assert.NoError(t, err)
+	return id
+}
+
+func ids(todos []model.TodoItem) []string {
+	arr := []string{}
+	for _, todo := range todos {
+		arr = append(arr, todo.ID)
+	}
+	return arr
+}
+
+func testTodos(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	id, err := s.AddItem(ctx, model.TodoItem{Name: "todo1", Date: date, Position: 2, UserID: userID})
+	assert.NoError(t, err)
+	_, err = uuid.FromString(id)
+	assert.NoError(t, err, "id is uuid")
+
+	todo, err := s.GetItem(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, todo.ID)
+	assert.Equal(t, "todo1", todo.Name)
+	assert.Equal(t, model.StatusNew, todo.Status, "status defaults to new")
+	assert.Equal(t, 2, todo.Position)
+	assert.Equal(t, 1, todo.Version, "version starts at 1")
+	assert.Equal(t, userID, todo.UserID)
+	assert.True(t, date.Equal(todo.Date))
+
+	todo.Name = "todo2"
+	todo.Status = model.StatusDone
+	todo.Date = date.Add(time.Hour)
+	assert.NoError(t, s.UpdateItem(ctx, todo))
+	got, err := s.GetItem(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "todo2", got.Name)
+	assert.Equal(t, model.StatusDone, got.Status)
+	assert.True(t, date.Add(time.Hour).Equal(got.Date))
+	assert.Equal(t, 2, got.Version, "update increments version")
+
+	got.Version = 7
+	assert.NoError(t, s.UpdateItem(ctx, got))
+	got, err = s.GetItem(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, got.Version, "given version is ignored")
+
+	assert.NoError(t, s.DeleteItem(ctx, id))
+	got, err = s.GetItem(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TodoItem{}, got)
+
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Empty(t, todos)
+}
+
+func testTodoFilters(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	otherID := addUser(t, s, "proxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	early := addItem(t, s, model.TodoItem{Name: "Pay invoice", Date: date, UserID: userID})
+	late := addItem(t, s, model.TodoItem{Name: "Buy milk", Date: date.Add(48 * time.Hour), UserID: userID})
+	done := addItem(t, s, model.TodoItem{Name: "Walk dog", Date: date.Add(24 * time.Hour), Status: model.StatusDone, UserID: userID})
+	notMine := addItem(t, s, model.TodoItem{Name: "Not mine", Date: date, UserID: otherID})
+
+	from, to := date.Add(time.Hour), date.Add(47*time.Hour)
+	expr, err := query.Parse(`name~invoice OR status:done`)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter storage.TodoFilter
+		want   []string
+	}{
+		{"user", storage.TodoFilter{}, []string{early, done, late}},
+		{"status", storage.TodoFilter{Status: model.StatusNew}, []string{early, late}},
+		{"from date", storage.TodoFilter{FromDate: &from}, []string{done, late}},
+		{"to date", storage.TodoFilter{ToDate: &to}, []string{early, done}},
+		{"date range", storage.TodoFilter{FromDate: &from, ToDate: &to}, []string{done}},
+		{"query", storage.TodoFilter{Query: expr}, []string{early, done}},
+		{"ids", storage.TodoFilter{IDs: []string{late, early, notMine, "not-a-uuid"}}, []string{early, late}},
+		{"malformed ids", storage.TodoFilter{IDs: []string{"not-a-uuid"}}, []string{}},
+		{"limit", storage.TodoFilter{Limit: 2}, []string{early, done}},
+		{"sort", storage.TodoFilter{Sort: []storage.SortField{{Field: "name"}}}, []string{late, early, done}},
+		{"sort desc", storage.TodoFilter{Sort: []storage.SortField{{Field: "date", Desc: true}}}, []string{late, done, early}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.filter.UserID = userID
+			todos, err := s.GetAllItems(ctx, tt.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ids(todos))
+		})
+	}
+
+	t.Run("fields", func(t *testing.T) {
+		todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Fields: []string{"name"}, Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, todos, 1)
+		assert.Equal(t, early, todos[0].ID)
+		assert.Equal(t, "Pay invoice", todos[0].Name)
+		assert.Empty(t, todos[0].Status)
+		assert.Empty(t, todos[0].UserID)
+	})
+}
+
+func testBatches(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	results, err := s.AddItems(ctx, []model.TodoItem{
+		{Name: "a", Date: date, UserID: userID},
+		{Name: "b", Date: date.Add(time.Hour), Status: model.StatusDone, AllDay: true, UserID: userID},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	a, b := results[0].ID, results[1].ID
+	for _, r := range results {
+		_, err := uuid.FromString(r.ID)
+		assert.NoError(t, err, "id is uuid")
+		assert.Equal(t, 1, r.Version)
+		assert.False(t, r.Missing)
+	}
+	todo, err := s.GetItem(ctx, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", todo.Name)
+	assert.Equal(t, model.StatusNew, todo.Status, "status defaults to new")
+	assert.True(t, date.Equal(todo.Date))
+	todo, err = s.GetItem(ctx, b)
+	assert.NoError(t, err)
+	assert.Equal(t, model.StatusDone, todo.Status)
+	assert.True(t, todo.AllDay)
+
+	missing := uuid.NewV4().String()
+	results, err = s.UpdateItems(ctx, []model.TodoItem{
+		{ID: b, Name: "b2", Date: date, UserID: userID},
+		{ID: missing, Name: "missing", Date: date, UserID: userID},
+		{ID: "not-a-uuid", Name: "malformed", Date: date, UserID: userID},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.ItemResult{{ID: b, Version: 2}, {ID: missing, Missing: true}, {ID: "not-a-uuid", Missing: true}}, results)
+	todo, err = s.GetItem(ctx, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "b2", todo.Name)
+	assert.Equal(t, 2, todo.Version)
+
+	results, err = s.DeleteItems(ctx, []string{missing, "not-a-uuid", a})
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.ItemResult{{ID: missing, Missing: true}, {ID: "not-a-uuid", Missing: true}, {ID: a}}, results)
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{b}, ids(todos))
+
+	failed := errors.New("failed")
+	err = s.WithTx(ctx, func(tx storage.Storage) error {
+		if _, err := tx.AddItems(ctx, []model.TodoItem{{Name: "c", UserID: userID}}); err != nil {
+			return err
+		}
+		if _, err := tx.DeleteItems(ctx, []string{b}); err != nil {
+			return err
+		}
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	todos, err = s.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{b}, ids(todos), "batches join transaction")
+}
+
+func testTodoPages(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	all := []string{}
+	for k := 0; k < 7; k++ {
+		// pairs of todos share date, so ties are broken by id
+		all = append(all, addItem(t, s, model.TodoItem{Name: "todo", Date: date.Add(time.Duration(k/2) * time.Hour), UserID: userID}))
+	}
+
+	for _, sort := range [][]storage.SortField{nil, {{Field: "name"}, {Field: "date", Desc: true}}} {
+		want, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Sort: sort})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, all, ids(want))
+
+		got := []string{}
+		filter := storage.TodoFilter{UserID: userID, Sort: sort, Limit: 3}
+		for {
+			page, err := s.GetAllItems(ctx, filter)
+			assert.NoError(t, err)
+			got = append(got, ids(page)...)
+			if len(page) < filter.Limit {
+				break
+			}
+			filter.After = &page[len(page)-1]
+		}
+		assert.Equal(t, ids(want), got)
+	}
+}
+
+func testTimeZones(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", newYork)
+
+	user, err := s.GetUser(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, newYork.String(), user.Location.String())
+
+	// late evening in New York is next day in UTC
+	evening := time.Date(2021, 3, 14, 23, 0, 0, 0, newYork)
+	id := addItem(t, s, model.TodoItem{Name: "evening", Date: evening.In(tokyo), UserID: userID})
+	allDay := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	allDayID := addItem(t, s, model.TodoItem{Name: "holiday", Date: allDay, AllDay: true, UserID: userID})
+
+	todo, err := s.GetItem(ctx, id)
+	assert.NoError(t, err)
+	assert.True(t, evening.Equal(todo.Date))
+	assert.Equal(t, newYork.String(), todo.Date.Location().String(), "date is in users location")
+
+	todo, err = s.GetItem(ctx, allDayID)
+	assert.NoError(t, err)
+	assert.True(t, allDay.Equal(todo.Date))
+	assert.Equal(t, time.UTC, todo.Date.Location(), "all-day date stays in UTC")
+
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+	assert.NoError(t, err)
+	for _, todo := range todos {
+		if !todo.AllDay {
+			assert.Equal(t, newYork.String(), todo.Date.Location().String())
+		}
+	}
+
+	from := time.Date(2021, 3, 15, 12, 0, 0, 0, tokyo)
+	todos, err = s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, FromDate: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{id}, ids(todos), "dates are compared as instants")
+
+	expr, err := query.Parse("due:2021-03-14")
+	assert.NoError(t, err)
+	todos, err = s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Query: expr})
+	assert.NoError(t, err)
+	assert.Contains(t, ids(todos), id, "due date is calendar date in users location")
+
+	expr, err = query.Parse("due:2021-03-15")
+	assert.NoError(t, err)
+	todos, err = s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Query: expr})
+	assert.NoError(t, err)
+	assert.NotContains(t, ids(todos), id)
+}
+
+func testUsers(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	id, err := s.AddUser(ctx, model.User{UserName: "alice", FirstName: "Alice", LastName: "Smith", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+	user, err := s.GetUser(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, "alice", user.UserName)
+	assert.Equal(t, "Alice", user.FirstName)
+	assert.Equal(t, "Smith", user.LastName)
+	assert.Equal(t, "hash", user.Password)
+
+	user.LastName = "Jones"
+	assert.NoError(t, s.UpdateUser(ctx, user))
+	user, err = s.GetUser(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Jones", user.LastName)
+
+	bob, err := s.AddUser(ctx, model.User{UserName: "bob", FirstName: "Robert", LastName: "Alison", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+	carol, err := s.AddUser(ctx, model.User{UserName: "carol", FirstName: "Carol", LastName: "Malice", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+
+	users, err := s.GetAllUsers(ctx, storage.UserFilter{UserName: "bob"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, bob, users[0].ID)
+
+	users, err = s.GetAllUsers(ctx, storage.UserFilter{Query: "ALI", Fields: storage.PublicUserFields})
+	assert.NoError(t, err)
+	got := []string{}
+	for _, u := range users {
+		assert.Empty(t, u.Password, "password is not loaded")
+		got = append(got, u.ID)
+	}
+	assert.ElementsMatch(t, []string{id, bob}, got)
+
+	users, err = s.GetAllUsers(ctx, storage.UserFilter{IDs: []string{carol, "not-a-uuid"}, Fields: []string{"username"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.User{{ID: carol, UserName: "carol"}}, users, "only id and requested fields are loaded")
+
+	all, err := s.GetAllUsers(ctx, storage.UserFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+	paged := []model.User{}
+	filter := storage.UserFilter{Limit: 2}
+	for {
+		page, err := s.GetAllUsers(ctx, filter)
+		assert.NoError(t, err)
+		paged = append(paged, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.AfterID = page[len(page)-1].ID
+	}
+	assert.Equal(t, all, paged)
+
+	_, err = s.AddUser(ctx, model.User{UserName: "Bob", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.ErrorIs(t, err, model.ErrConflict, "usernames are unique ignoring case")
+	user, err = s.GetUser(ctx, bob)
+	assert.NoError(t, err)
+	user.UserName = "CAROL"
+	assert.ErrorIs(t, s.UpdateUser(ctx, user), model.ErrConflict)
+	user.UserName = "BOB"
+	assert.NoError(t, s.UpdateUser(ctx, user), "user keeps its own username")
+	users, err = s.GetAllUsers(ctx, storage.UserFilter{UserName: "bob"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1, "username filter ignores case")
+
+	// backends agree on lower case usernames, not on full Unicode case folding
+	_, err = s.AddUser(ctx, model.User{UserName: "ſam", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err)
+	_, err = s.AddUser(ctx, model.User{UserName: "SAM", Password: "hash", Location: model.CustomLocation{Location: time.UTC}})
+	assert.NoError(t, err, "long s is not lower case s")
+	users, err = s.GetAllUsers(ctx, storage.UserFilter{UserName: "sam"})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	assert.NoError(t, s.DeleteUser(ctx, carol))
+	user, err = s.GetUser(ctx, carol)
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{}, user)
+}
+
+func testUserDeletion(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	heirID := addUser(t, s, "heir", time.UTC)
+	otherID := addUser(t, s, "proxy", time.UTC)
+
+	moved := addItem(t, s, model.TodoItem{Name: "moved", UserID: userID})
+	kept := addItem(t, s, model.TodoItem{Name: "kept", UserID: otherID})
+	assert.NoError(t, s.ReassignItems(ctx, userID, heirID))
+
+	todo, err := s.GetItem(ctx, moved)
+	assert.NoError(t, err)
+	assert.Equal(t, heirID, todo.UserID)
+	assert.Equal(t, 2, todo.Version, "reassign increments version")
+	todo, err = s.GetItem(ctx, kept)
+	assert.NoError(t, err)
+	assert.Equal(t, otherID, todo.UserID)
+
+	for _, id := range []string{heirID, otherID} {
+		_, err := s.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: id})
+		assert.NoError(t, err)
+		_, err = s.AddNotification(ctx, model.Notification{Type: model.NotificationMention, UserID: id, Message: "hello"})
+		assert.NoError(t, err)
+		_, err = s.AddView(ctx, model.View{Name: "all", UserID: id})
+		assert.NoError(t, err)
+		assert.NoError(t, s.UpdateBoard(ctx, model.Board{UserID: id, Columns: []model.BoardColumn{{Status: model.StatusNew}}}))
+	}
+
+	assert.NoError(t, s.DeleteUser(ctx, heirID))
+
+	todo, err = s.GetItem(ctx, moved)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TodoItem{}, todo, "todos are deleted with user")
+	activities, err := s.GetAllActivities(ctx, storage.ActivityFilter{UserID: heirID})
+	assert.NoError(t, err)
+	assert.Empty(t, activities)
+	notifications, err := s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: heirID})
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+	views, err := s.GetAllViews(ctx, heirID)
+	assert.NoError(t, err)
+	assert.Empty(t, views)
+	board, err := s.GetBoard(ctx, heirID)
+	assert.NoError(t, err)
+	assert.Empty(t, board.Columns)
+
+	// records of other users are kept
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: otherID})
+	assert.NoError(t, err)
+	assert.Len(t, todos, 1)
+	activities, err = s.GetAllActivities(ctx, storage.ActivityFilter{UserID: otherID})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+	notifications, err = s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: otherID})
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+	views, err = s.GetAllViews(ctx, otherID)
+	assert.NoError(t, err)
+	assert.Len(t, views, 1)
+	board, err = s.GetBoard(ctx, otherID)
+	assert.NoError(t, err)
+	assert.Len(t, board.Columns, 1)
+}
+
+func testActivities(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	otherID := addUser(t, s, "proxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	add := func(typ, userID string, date time.Time) string {
+		id, err := s.AddActivity(ctx, model.Activity{Type: typ, UserID: userID, TodoID: "todo", TodoName: "Pay invoice", Date: date})
+		assert.NoError(t, err)
+		return id
+	}
+	created := add(model.ActivityTodoCreated, userID, date)
+	completed := add(model.ActivityTodoCompleted, userID, date.Add(time.Hour))
+	deleted := add(model.ActivityTodoDeleted, userID, date.Add(2*time.Hour))
+	add(model.ActivityTodoCreated, otherID, date)
+
+	activities, err := s.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+	assert.NoError(t, err)
+	got := []string{}
+	for _, a := range activities {
+		got = append(got, a.ID)
+	}
+	assert.Equal(t, []string{deleted, completed, created}, got, "newest first")
+	assert.Equal(t, "Pay invoice", activities[0].TodoName)
+	assert.True(t, date.Add(2*time.Hour).Equal(activities[0].Date))
+
+	activities, err = s.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID, Types: []string{model.ActivityTodoCreated, model.ActivityTodoDeleted}})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 2)
+
+	before := &storage.Cursor{Date: activities[0].Date, ID: activities[0].ID}
+	activities, err = s.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID, Before: before, Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+	assert.Equal(t, completed, activities[0].ID)
+}
+
+func testNotifications(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	otherID := addUser(t, s, "proxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	add := func(userID string, date time.Time) string {
+		id, err := s.AddNotification(ctx, model.Notification{Type: model.NotificationMention, UserID: userID, ActorID: otherID, Message: "hello", Date: date})
+		assert.NoError(t, err)
+		return id
+	}
+	old := add(userID, date)
+	first := add(userID, date.Add(time.Hour))
+	second := add(userID, date.Add(2*time.Hour))
+	add(otherID, date.Add(time.Hour))
+
+	n, err := s.GetNotification(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", n.Message)
+	assert.Equal(t, otherID, n.ActorID)
+	assert.False(t, n.Read)
+
+	count, err := s.CountUnreadNotifications(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.NoError(t, s.MarkNotificationRead(ctx, second))
+	notifications, err := s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: userID, UnreadOnly: true})
+	assert.NoError(t, err)
+	got := []string{}
+	for _, n := range notifications {
+		got = append(got, n.ID)
+	}
+	assert.Equal(t, []string{first, old}, got, "newest first")
+
+	assert.NoError(t, s.MarkAllNotificationsRead(ctx, userID))
+	count, err = s.CountUnreadNotifications(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	count, err = s.CountUnreadNotifications(ctx, otherID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count, "other users notifications stay unread")
+
+	reminder, err := s.AddNotification(ctx, model.Notification{Type: model.NotificationReminder, UserID: userID, TodoID: "todo", Message: "due", Date: date})
+	assert.NoError(t, err)
+	notifications, err = s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: userID, Type: model.NotificationReminder, TodoID: "todo"})
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, reminder, notifications[0].ID)
+	}
+	notifications, err = s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: userID, Type: model.NotificationReminder, TodoID: "other"})
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	assert.NoError(t, s.DeleteNotificationsBefore(ctx, date.Add(time.Minute)))
+	notifications, err = s.GetAllNotifications(ctx, storage.NotificationFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 2)
+}
+
+// testStats checks that throughput is counted from creation and completion times of todos.
+func testStats(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", newYork)
+	// 2021-10-02 01:00 UTC is still October 1st in New York.
+	date := time.Date(2021, 10, 2, 1, 0, 0, 0, time.UTC)
+
+	done := addItem(t, s, model.TodoItem{Name: "done", UserID: userID, Status: model.StatusDone, Date: date, Created: date, Completed: date.Add(24 * time.Hour)})
+	reopened := addItem(t, s, model.TodoItem{Name: "reopened", UserID: userID, Status: model.StatusDone, Date: date, Created: date.Add(time.Hour), Completed: date.Add(2 * time.Hour)})
+	addItem(t, s, model.TodoItem{Name: "old", UserID: userID, Date: date.AddDate(0, 0, 5), Created: date.AddDate(-1, 0, 0)})
+	// all-day todos are stored at midnight UTC of their date, Now is October 2nd in New York
+	addItem(t, s, model.TodoItem{Name: "due today", UserID: userID, Date: time.Date(2021, 10, 2, 0, 0, 0, 0, time.UTC), AllDay: true})
+	addItem(t, s, model.TodoItem{Name: "due yesterday", UserID: userID, Date: time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC), AllDay: true})
+
+	todo, err := s.GetItem(ctx, done)
+	assert.NoError(t, err)
+	todo.Name = "done and renamed"
+	assert.NoError(t, s.UpdateItem(ctx, todo), "completion is kept while todo stays done")
+	todo, err = s.GetItem(ctx, reopened)
+	assert.NoError(t, err)
+	todo.Status = model.StatusNew
+	assert.NoError(t, s.UpdateItem(ctx, todo), "completion is cleared when todo is reopened")
+
+	stats, err := s.GetStats(ctx, storage.StatsFilter{
+		UserID:   userID,
+		From:     time.Date(2021, 9, 1, 0, 0, 0, 0, newYork),
+		To:       time.Date(2021, 11, 1, 0, 0, 0, 0, newYork),
+		Bucket:   model.BucketDay,
+		Location: newYork,
+		Now:      date.AddDate(0, 0, 1),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{model.StatusDone: 1, model.StatusNew: 4}, stats.ByStatus)
+	assert.Equal(t, 2, stats.Overdue, "all-day todo is overdue after its local date")
+	assert.Equal(t, 0, stats.Untracked, "todos get creation time when added")
+	assert.Equal(t, float64(24*60*60), stats.AvgTimeToComplete)
+	assert.Equal(t, []model.StatsBucket{
+		{Start: time.Date(2021, 10, 1, 0, 0, 0, 0, newYork), Created: 2},
+		{Start: time.Date(2021, 10, 2, 0, 0, 0, 0, newYork), Completed: 1},
+	}, stats.Buckets)
+}
+
+func testBoard(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+
+	board, err := s.GetBoard(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Board{UserID: userID, Columns: []model.BoardColumn{}}, board)
+
+	columns := []model.BoardColumn{{Status: model.StatusNew}, {Status: "doing", WIPLimit: 3}, {Status: model.StatusDone}}
+	assert.NoError(t, s.UpdateBoard(ctx, model.Board{UserID: userID, Columns: columns}))
+	board, err = s.GetBoard(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Board{UserID: userID, Columns: columns}, board)
+
+	assert.NoError(t, s.UpdateBoard(ctx, model.Board{UserID: userID, Columns: columns[:1]}))
+	board, err = s.GetBoard(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, columns[:1], board.Columns, "columns are replaced")
+
+	err = s.WithTx(ctx, func(tx storage.Storage) error {
+		if err := tx.LockBoard(ctx, userID); err != nil {
+			return err
+		}
+		return tx.UpdateBoard(ctx, model.Board{UserID: userID, Columns: columns})
+	})
+	assert.NoError(t, err)
+	board, err = s.GetBoard(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, columns, board.Columns)
+
+	card := addItem(t, s, model.TodoItem{Name: "card", Position: 3, UserID: userID})
+	assert.NoError(t, s.UpdateItemPositions(ctx, []model.TodoItem{{ID: card, Position: 1}}))
+	todo, err := s.GetItem(ctx, card)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, todo.Position)
+	assert.Equal(t, "card", todo.Name)
+	assert.Equal(t, 1, todo.Version, "position change keeps version")
+}
+
+func testViews(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	otherID := addUser(t, s, "proxy", time.UTC)
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	id, err := s.AddView(ctx, model.View{Name: "work", Status: model.StatusNew, FromDate: &from, Query: "name~invoice", Sort: "-date", UserID: userID})
+	assert.NoError(t, err)
+	other, err := s.AddView(ctx, model.View{Name: "home", UserID: userID})
+	assert.NoError(t, err)
+	_, err = s.AddView(ctx, model.View{Name: "all", UserID: otherID})
+	assert.NoError(t, err)
+
+	view, err := s.GetView(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, "work", view.Name)
+	assert.Equal(t, model.StatusNew, view.Status)
+	assert.Equal(t, "name~invoice", view.Query)
+	assert.Equal(t, "-date", view.Sort)
+	assert.Equal(t, userID, view.UserID)
+	if assert.NotNil(t, view.FromDate) {
+		assert.True(t, from.Equal(*view.FromDate))
+	}
+	assert.Nil(t, view.ToDate)
+
+	view.Name = "office"
+	assert.NoError(t, s.UpdateView(ctx, view))
+	views, err := s.GetAllViews(ctx, userID)
+	assert.NoError(t, err)
+	if assert.Len(t, views, 2) {
+		assert.Equal(t, other, views[0].ID, "views are ordered by name")
+		assert.Equal(t, "office", views[1].Name)
+	}
+
+	assert.NoError(t, s.DeleteView(ctx, id))
+	view, err = s.GetView(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, model.View{}, view)
+}
+
+func testOutbox(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	date := time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	add := func(tx storage.Storage, eventType string, date time.Time) string {
+		id, err := tx.AddEvent(ctx, model.Event{Type: eventType, UserID: userID, Payload: []byte(`{"id": "1", "name": "a"}`), Date: date})
+		assert.NoError(t, err)
+		return id
+	}
+	first := add(s, model.EventTodoCreated, date)
+	second := add(s, model.EventTodoUpdated, date)
+	err := s.WithTx(ctx, func(tx storage.Storage) error {
+		add(tx, model.EventTodoDeleted, date.Add(2*time.Minute))
+		return errors.New("failed")
+	})
+	assert.Error(t, err)
+	var third string
+	err = s.WithTx(ctx, func(tx storage.Storage) error {
+		third = add(tx, model.EventUserDeleted, date.Add(-time.Minute))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, s.DeleteUser(ctx, userID))
+
+	events, err := s.GetEvents(ctx, 0)
+	assert.NoError(t, err)
+	got := []string{}
+	for _, e := range events {
+		got = append(got, e.ID)
+	}
+	assert.Equal(t, []string{first, second, third}, got, "in order added, rolled back event is discarded, events outlive user")
+	assert.True(t, events[0].Seq < events[1].Seq && events[1].Seq < events[2].Seq, "seq grows")
+	assert.Equal(t, model.EventTodoCreated, events[0].Type)
+	assert.Equal(t, userID, events[0].UserID)
+	assert.JSONEq(t, `{"id": "1", "name": "a"}`, string(events[0].Payload))
+	assert.True(t, date.Equal(events[0].Date))
+
+	events, err = s.GetEvents(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	assert.NoError(t, s.DeleteEvent(ctx, first))
+	events, err = s.GetEvents(ctx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, second, events[0].ID)
+}
+
+func testSearch(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", newYork)
+	otherID := addUser(t, s, "proxy", time.UTC)
+
+	id := addItem(t, s, model.TodoItem{Name: "Pay invoice", UserID: userID})
+	addItem(t, s, model.TodoItem{Name: "Buy milk", UserID: userID})
+	addItem(t, s, model.TodoItem{Name: "Pay invoice", UserID: otherID})
+
+	results, err := s.SearchItems(ctx, storage.TodoSearch{Query: "invoice", UserID: userID})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, id, results[0].Todo.ID)
+		assert.Equal(t, newYork.String(), results[0].Todo.Date.Location().String())
+	}
+
+	results, err = s.SearchItems(ctx, storage.TodoSearch{Query: "bread", UserID: userID})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	addItem(t, s, model.TodoItem{Name: "<script>alert(1)</script> send report", UserID: userID})
+	results, err = s.SearchItems(ctx, storage.TodoSearch{Query: "report", UserID: userID})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.NotContains(t, results[0].Snippet, "<script>")
+		assert.Contains(t, results[0].Snippet, "&lt;")
+		assert.Contains(t, results[0].Snippet, "<b>report</b>")
+	}
+}
+
+// testNotFound checks that missing records are reported as zero values without error.
+func testNotFound(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", newYork)
+	missing := uuid.NewV4().String()
+
+	todo, err := s.GetItem(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TodoItem{}, todo)
+
+	user, err := s.GetUser(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{}, user)
+
+	view, err := s.GetView(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.View{}, view)
+
+	n, err := s.GetNotification(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.Notification{}, n)
+	n, err = s.GetNotification(ctx, "malformed")
+	assert.NoError(t, err, "id that is not uuid is not found")
+	assert.Equal(t, model.Notification{}, n)
+
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: missing})
+	assert.NoError(t, err)
+	assert.Empty(t, todos)
+
+	assert.NoError(t, s.DeleteItem(ctx, missing))
+	assert.NoError(t, s.DeleteUser(ctx, missing))
+	assert.NoError(t, s.DeleteView(ctx, missing))
+	assert.NoError(t, s.MarkNotificationRead(ctx, missing))
+	assert.NoError(t, s.MarkNotificationRead(ctx, "malformed"))
+
+	// updates of missing records do not create them
+	assert.NoError(t, s.UpdateItem(ctx, model.TodoItem{ID: missing, Name: "ghost", UserID: userID}))
+	todo, err = s.GetItem(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TodoItem{}, todo)
+
+	assert.NoError(t, s.UpdateUser(ctx, model.User{ID: missing, UserName: "ghost", Location: model.CustomLocation{Location: time.UTC}}))
+	user, err = s.GetUser(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{}, user)
+
+	assert.NoError(t, s.UpdateView(ctx, model.View{ID: missing, Name: "ghost", UserID: userID}))
+	view, err = s.GetView(ctx, missing)
+	assert.NoError(t, err)
+	assert.Equal(t, model.View{}, view)
+}
+
+func testTransactions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	failed := errors.New("failed")
+
+	var rolledBack string
+	err := s.WithTx(ctx, func(tx storage.Storage) error {
+		rolledBack = addItem(t, tx, model.TodoItem{Name: "rolled back", UserID: userID})
+		todo, err := tx.GetItem(ctx, rolledBack)
+		assert.NoError(t, err)
+		assert.Equal(t, rolledBack, todo.ID, "changes are visible inside transaction")
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	todo, err := s.GetItem(ctx, rolledBack)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TodoItem{}, todo)
+
+	var committed string
+	err = s.WithTx(ctx, func(tx storage.Storage) error {
+		assert.NoError(t, tx.LockUsers(ctx, userID, "missing"))
+		committed = addItem(t, tx, model.TodoItem{Name: "committed", UserID: userID})
+		return tx.WithTx(ctx, func(tx storage.Storage) error {
+			_, err := tx.AddActivity(ctx, model.Activity{Type: model.ActivityTodoCreated, UserID: userID, TodoID: committed})
+			return err
+		})
+	})
+	assert.NoError(t, err)
+	todo, err = s.GetItem(ctx, committed)
+	assert.NoError(t, err)
+	assert.Equal(t, committed, todo.ID)
+	activities, err := s.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+
+	err = s.WithTx(ctx, func(tx storage.Storage) error {
+		todo, err := tx.GetItem(ctx, committed)
+		assert.NoError(t, err)
+		todo.Name = "renamed"
+		assert.NoError(t, tx.UpdateItem(ctx, todo))
+		assert.NoError(t, tx.DeleteUser(ctx, userID))
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	todo, err = s.GetItem(ctx, committed)
+	assert.NoError(t, err)
+	assert.Equal(t, "committed", todo.Name, "rolled back update restores todo")
+	user, err := s.GetUser(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, userID, user.ID, "rolled back deletion restores user")
+	activities, err = s.GetAllActivities(ctx, storage.ActivityFilter{UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+	results, err := s.SearchItems(ctx, storage.TodoSearch{Query: "committed", UserID: userID})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	results, err = s.SearchItems(ctx, storage.TodoSearch{Query: "renamed", UserID: userID})
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+// testConcurrency checks that read-check-write transactions do not lose updates.
+func testConcurrency(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	userID := addUser(t, s, "roxy", time.UTC)
+	counter := addItem(t, s, model.TodoItem{Name: "counter", UserID: userID})
+
+	const workers, rounds = 4, 10
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < rounds; k++ {
+				_, err := s.AddItem(ctx, model.TodoItem{Name: "concurrent todo", UserID: userID})
+				assert.NoError(t, err)
+				err = s.WithTx(ctx, func(tx storage.Storage) error {
+					todo, err := tx.GetItem(ctx, counter)
+					if err != nil {
+						return err
+					}
+					todo.Position++
+					return tx.UpdateItem(ctx, todo)
+				})
+				assert.NoError(t, err)
+				_, err = s.GetAllItems(ctx, storage.TodoFilter{UserID: userID})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	todo, err := s.GetItem(ctx, counter)
+	assert.NoError(t, err)
+	assert.Equal(t, workers*rounds, todo.Position)
+	todos, err := s.GetAllItems(ctx, storage.TodoFilter{UserID: userID, Status: model.StatusNew})
+	assert.NoError(t, err)
+	assert.Len(t, todos, workers*rounds+1)
+}
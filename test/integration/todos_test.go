@@ -1 +1,4 @@
+//go:build integration
+// +build integration
+
 package integration_test